@@ -3,6 +3,7 @@ package block
 import (
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/block/cube/trace"
+	"github.com/df-mc/dragonfly/server/event"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/particle"
@@ -90,19 +91,16 @@ func (c ExplosionConfig) Explode(w *world.World, explosionPos mgl64.Vec3) {
 		math.Ceil(explosionPos[2]+d+1),
 	)
 
+	affectedEntities := make([]world.Entity, 0, 32)
 	for _, e := range w.EntitiesWithin(box.Grow(2), nil) {
 		pos := e.Position()
 		if !e.Type().BBox(e).Translate(pos).IntersectsWith(box) {
 			continue
 		}
-		dist := pos.Sub(pos).Len()
-		if dist >= d {
+		if pos.Sub(explosionPos).Len() >= d {
 			continue
 		}
-		if explodable, ok := e.(ExplodableEntity); ok {
-			impact := (1 - dist/d) * exposure(pos, e)
-			explodable.Explode(explosionPos, impact, c)
-		}
+		affectedEntities = append(affectedEntities, e)
 	}
 
 	affectedBlocks := make([]cube.Pos, 0, 32)
@@ -128,20 +126,36 @@ func (c ExplosionConfig) Explode(w *world.World, explosionPos mgl64.Vec3) {
 			}
 		}
 	}
+
+	itemDropChance, spawnFire := 1/c.Size, c.SpawnFire
+	if c.DisableItemDrops {
+		itemDropChance = 0
+	}
+	ctx := event.C()
+	if w.Handler().HandleExplosion(ctx, explosionPos, &affectedEntities, &affectedBlocks, &itemDropChance, &spawnFire); ctx.Cancelled() {
+		return
+	}
+
+	for _, e := range affectedEntities {
+		if explodable, ok := e.(ExplodableEntity); ok {
+			impact := (1 - e.Position().Sub(explosionPos).Len()/d) * exposure(explosionPos, e)
+			explodable.Explode(explosionPos, impact, c)
+		}
+	}
 	for _, pos := range affectedBlocks {
 		bl := w.Block(pos)
 		if explodable, ok := bl.(Explodable); ok {
 			explodable.Explode(explosionPos, pos, w, c)
 		} else if breakable, ok := bl.(Breakable); ok {
 			w.SetBlock(pos, nil, nil)
-			if !c.DisableItemDrops && 1/c.Size > r.Float64() {
+			if itemDropChance > r.Float64() {
 				for _, drop := range breakable.BreakInfo().Drops(item.ToolNone{}, nil) {
 					dropItem(w, drop, pos.Vec3Centre())
 				}
 			}
 		}
 	}
-	if c.SpawnFire {
+	if spawnFire {
 		for _, pos := range affectedBlocks {
 			if r.Intn(3) == 0 {
 				if _, ok := w.Block(pos).(Air); ok && w.Block(pos.Side(cube.FaceDown)).Model().FaceSolid(pos, cube.FaceUp, w) {
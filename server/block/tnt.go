@@ -29,12 +29,18 @@ func (t TNT) Activate(pos cube.Pos, _ cube.Face, w *world.World, u item.User, ct
 
 // Ignite ...
 func (t TNT) Ignite(pos cube.Pos, w *world.World) bool {
+	if explodes, _ := w.Gamerule("tntExplodes").(bool); !explodes {
+		return false
+	}
 	spawnTnt(pos, w, time.Second*4)
 	return true
 }
 
 // Explode ...
 func (t TNT) Explode(_ mgl64.Vec3, pos cube.Pos, w *world.World, _ ExplosionConfig) {
+	if explodes, _ := w.Gamerule("tntExplodes").(bool); !explodes {
+		return
+	}
 	spawnTnt(pos, w, time.Second/2+time.Duration(rand.Intn(int(time.Second+time.Second/2))))
 }
 
@@ -48,6 +48,7 @@ type Command struct {
 	description string
 	usage       string
 	aliases     []string
+	hidden      bool
 }
 
 // New returns a new Command using the name and description passed. The Runnable passed must be a
@@ -115,6 +116,19 @@ func (cmd Command) Aliases() []string {
 	return cmd.aliases
 }
 
+// Hidden specifies if the command is hidden from the /help list. Hidden commands may still be run by sources
+// that are allowed to run them.
+func (cmd Command) Hidden() bool {
+	return cmd.hidden
+}
+
+// Hide returns a copy of the command that is hidden from the /help list, while remaining runnable. It may be
+// passed to Register like any other command.
+func (cmd Command) Hide() Command {
+	cmd.hidden = true
+	return cmd
+}
+
 // Execute executes the Command as a source with the args passed. The args are parsed assuming they do not
 // start with the command name. Execute will attempt to parse and execute one Runnable at a time. If one of
 // the Runnable was able to parse args correctly, it will be executed and no more Runnables will be attempted
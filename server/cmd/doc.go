@@ -24,4 +24,8 @@
 // Commands may be registered using the cmd.Register() method. By itself, this method will not ensure that the
 // client will be able to use the command: The user of the cmd package must handle commands itself and run the
 // appropriate one using the cmd.ByAlias function.
+//
+// A default /help command is registered that lists all commands a source is allowed to run, together with
+// their usage. It may be replaced by registering another command named "help". Commands hidden using
+// Command.Hide are left out of the list, but may still be run.
 package cmd
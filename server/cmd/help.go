@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+)
+
+// helpPageSize is the number of commands shown on a single page of the /help list.
+const helpPageSize = 7
+
+// init registers the default /help command. It may be overridden by registering another command with the name
+// "help".
+func init() {
+	Register(New("help", "Provides help for commands.", []string{"?"}, HelpPage{}, HelpCommand{}))
+}
+
+// HelpPage is the Runnable of the default /help command that lists a page of all commands that the Source
+// is allowed to run.
+type HelpPage struct {
+	Page Optional[int] `cmd:"page"`
+}
+
+// HelpCommand is the Runnable of the default /help command that shows the usage of a single command.
+type HelpCommand struct {
+	Command string `cmd:"command"`
+}
+
+// Run ...
+func (h HelpPage) Run(src Source, o *Output) {
+	commands := helpCommands(src)
+	pages := (len(commands) + helpPageSize - 1) / helpPageSize
+	if pages == 0 {
+		pages = 1
+	}
+	page := h.Page.LoadOr(1)
+	if page < 1 {
+		page = 1
+	} else if page > pages {
+		page = pages
+	}
+	o.Printf("§2--- Showing help page %v of %v (/help <page>) ---", page, pages)
+
+	start := (page - 1) * helpPageSize
+	end := start + helpPageSize
+	if end > len(commands) {
+		end = len(commands)
+	}
+	for _, c := range commands[start:end] {
+		for _, usage := range strings.Split(c.Usage(), "\n") {
+			o.Print(usage)
+		}
+	}
+	o.Print("§2Tip: Use the <tab> key while typing a command to auto-complete the command or its arguments")
+}
+
+// Run ...
+func (h HelpCommand) Run(src Source, o *Output) {
+	c, ok := ByAlias(strings.TrimPrefix(strings.ToLower(h.Command), "/"))
+	if !ok || c.Hidden() || len(c.Runnables(src)) == 0 {
+		o.Errorf("Unknown command: %v. Please check that the command exists and that you have permission to use it.", h.Command)
+		return
+	}
+	o.Printf("§e%v:", c.Name())
+	if c.Description() != "" {
+		o.Print(c.Description())
+	}
+	o.Print("Usage:")
+	for _, usage := range strings.Split(c.Usage(), "\n") {
+		o.Printf("- %v", usage)
+	}
+	if aliases := c.Aliases(); len(aliases) > 1 {
+		o.Printf("Aliases: %v", strings.Join(aliases, ", "))
+	}
+}
+
+// helpCommands returns all registered commands that are not hidden and that the Source passed is allowed to
+// run, sorted by their name.
+func helpCommands(src Source) []Command {
+	var commands []Command
+	for alias, c := range Commands() {
+		if c.Name() != alias || c.Hidden() || len(c.Runnables(src)) == 0 {
+			// Don't add duplicate entries for aliases.
+			continue
+		}
+		commands = append(commands, c)
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].Name() < commands[j].Name()
+	})
+	return commands
+}
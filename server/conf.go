@@ -3,7 +3,9 @@ package server
 import (
 	"fmt"
 	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/event"
 	"github.com/df-mc/dragonfly/server/internal/packbuilder"
 	"github.com/df-mc/dragonfly/server/player"
 	"github.com/df-mc/dragonfly/server/player/playerdb"
@@ -14,11 +16,16 @@ import (
 	"github.com/df-mc/dragonfly/server/world/mcdb"
 	"github.com/df-mc/goleveldb/leveldb/opt"
 	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft"
 	"github.com/sandertv/gophertunnel/minecraft/resource"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/exp/slices"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 )
 
 // Config contains options for starting a Minecraft server.
@@ -35,9 +42,14 @@ type Config struct {
 	// Name is the name of the server. By default, it is shown to users in the
 	// server list before joining the server and when opening the in-game menu.
 	Name string
+	// StatusProvider provides the server status shown to players in the server
+	// list. By default, StatusProvider shows the current name of the Server,
+	// the amount of players online and the maximum amount of players.
+	StatusProvider minecraft.ServerStatusProvider
 	// Resources is a slice of resource packs to use on the server. When joining
 	// the server, the player will then first be requested to download these
-	// resource packs.
+	// resource packs. Encrypted packs must have their content key set using
+	// resource.Pack.WithContentKey.
 	Resources []*resource.Pack
 	// ResourcesRequires specifies if the downloading of resource packs is
 	// required to join the server. If set to true, players will not be able to
@@ -53,26 +65,157 @@ type Config struct {
 	// the player has been banned, will prevent the player from joining.
 	Allower Allower
 	// AuthDisabled specifies if XBOX Live authentication should be disabled.
-	// Note that this should generally only be done for testing purposes or for
-	// local games. Allowing players to join without authentication is generally
-	// a security hazard.
+	// Note that this should generally only be done for testing purposes, for
+	// local games or behind a proxy that authenticates players itself.
+	// Allowing players to join without authentication is generally a security
+	// hazard: Players may then join with any name, UUID and XUID, so that they
+	// can impersonate other players, including operators.
 	AuthDisabled bool
 	// MaxPlayers is the maximum amount of players allowed to join the server at
 	// once.
 	MaxPlayers int
+	// QueueSize is the maximum amount of players that wait in a queue for a
+	// free slot when the Server is full. Queued players are admitted in the
+	// order they connected as soon as other players leave. If left as 0,
+	// players are refused when the Server is full.
+	QueueSize int
+	// ConnectionsPerSecond is the maximum amount of connections accepted from
+	// a single IP address every second. Connections exceeding this limit are
+	// closed before they spawn. If set to 0, the amount of connections is not
+	// limited.
+	ConnectionsPerSecond int
+	// TrustedProxies is a list of IP addresses and CIDR ranges, such as
+	// "10.0.0.0/8", of proxies that are trusted to forward the address of the
+	// clients connected to them. For connections made from one of these
+	// addresses, ProxyAddr is called to find the address of the client, which
+	// is then used for connection limits, the Allower, logging and
+	// player.Player.Addr. The entries are parsed once when the Server is
+	// created, and an invalid entry is a fatal error.
+	TrustedProxies []string
+	// ProxyAddr returns the address of the client connected to a trusted
+	// proxy, for example decoded from data added to the login by the proxy.
+	// If false is returned, the address of the proxy is used. If left as nil,
+	// TrustedProxies has no effect.
+	// Dragonfly does not decode any proxy protocol itself, such as the
+	// HAProxy PROXY protocol or a forwarded header, so ProxyAddr must be
+	// implemented for the proxy used. For the same reason, neither field can
+	// be set through the UserConfig.
+	ProxyAddr func(conn session.Conn) (net.Addr, bool)
+	// LoginTimeout is the maximum duration that spawning a player in the
+	// world may take after it has connected. Connections that take longer are
+	// disconnected. If left as 0, a timeout of 1 minute is used.
+	LoginTimeout time.Duration
+	// ReadTimeout is the duration after which players that have not sent any
+	// packets are disconnected, which closes half-open connections quickly.
+	// If left as 0, players are only disconnected once the underlying
+	// connection times out.
+	ReadTimeout time.Duration
+	// FlushImmediately specifies if packets sent to players are flushed to
+	// the connection as soon as they are written. By default, packets are
+	// buffered and flushed together once every tick of a session, which
+	// greatly reduces CPU usage and syscalls with many players at the cost
+	// of up to one tick of latency.
+	FlushImmediately bool
+	// RecoverPanics specifies if panics in the goroutines handling the
+	// connections and packets of players, for example caused by a
+	// player.Handler, are recovered. Recovered panics are logged with their
+	// stack trace and only the player involved is disconnected, rather than
+	// the program crashing.
+	RecoverPanics bool
+	// PanicFunc is called with the value and stack trace of every panic
+	// recovered if RecoverPanics is true. It may be used to escalate to a
+	// full restart, for example by notifying a supervisor. If left as nil,
+	// panics are only logged.
+	PanicFunc func(v any, stack []byte)
+	// AcceptedVersions is a list of game versions, such as "1.20.10", that
+	// clients must have to join the Server. Clients with other versions are
+	// disconnected with a message listing the accepted versions. If left
+	// empty, all versions supported by the protocol of the Server are
+	// accepted.
+	AcceptedVersions []string
+	// RejectDuplicateLogins specifies what happens when a player joins while a
+	// player with the same UUID is already online. If set to true, the new
+	// connection is refused. If false, the player already online is
+	// disconnected and its data is saved before the new connection joins.
+	RejectDuplicateLogins bool
 	// MaxChunkRadius is the maximum view distance that each player may have,
 	// measured in chunks. A chunk radius generally leads to more memory usage.
+	// MaxChunkRadius is limited to a value between 1 and 32. If left as 0, a
+	// maximum chunk radius of 12 is used.
 	MaxChunkRadius int
+	// ChunksPerTick is the maximum amount of chunks sent to a single player
+	// every tick. Chunks of players with a large chunk radius are spread over
+	// multiple ticks. If left as 0, 4 chunks are sent every tick.
+	ChunksPerTick int
 	// JoinMessage, QuitMessage and ShutdownMessage are the messages to send for
 	// when a player joins or quits the server and when the server shuts down,
 	// kicking all online players. JoinMessage and QuitMessage may have a '%v'
 	// argument, which will be replaced with the name of the player joining or
 	// quitting.
 	JoinMessage, QuitMessage, ShutdownMessage string
+	// WelcomeMessage is a chat message sent only to a player joining the
+	// server, once it has spawned. WelcomeTitle and WelcomeSubtitle are shown
+	// as a title to the player at the same time. The messages may hold colour
+	// codes or tags and the placeholders {name}, which is replaced with the
+	// name of the player, and {online}, which is replaced with the number of
+	// players online. Empty messages are not sent. JoinMessage and
+	// QuitMessage support the same placeholders.
+	WelcomeMessage, WelcomeTitle, WelcomeSubtitle string
+	// ChatCooldown limits the rate at which players may send chat messages.
+	// Messages exceeding the limit are dropped and the player is warned. The
+	// zero value does not limit chat messages.
+	ChatCooldown session.ChatCooldown
+	// PacketPolicy specifies how invalid packets sent by players are dealt
+	// with. The zero value disconnects players on the first invalid packet
+	// and ignores packets that are not expected.
+	PacketPolicy session.PacketPolicy
+	// ChatFormat is the format of chat messages sent by players. It must have
+	// two '%v' arguments, which are replaced with the name of the player and
+	// the message, in that order. The format may be changed per player using
+	// player.Player.SetChatFormat. If left empty, '<%v> %v' is used.
+	ChatFormat string
+	// IdleTimeout is the duration after which players that have not moved or
+	// sent any input are kicked. Players are warned shortly before being
+	// kicked. If left as 0, idle players are never kicked.
+	IdleTimeout time.Duration
+	// Messages holds the messages shown to players disconnected by the
+	// Server, so that they may be changed or translated. Empty fields are
+	// filled with the messages returned by DefaultMessages.
+	Messages Messages
+	// MaxReach is the maximum distance in blocks from the eyes of players to
+	// blocks and entities that they may interact with when not in creative
+	// mode. Interactions beyond this distance are rejected. If left as 0, a
+	// reach of 8 blocks is used.
+	MaxReach float64
+	// MaxMoveSpeed is the maximum horizontal distance in blocks that players
+	// may move per tick at the default movement speed. The distance is scaled
+	// by the speed of players, so that sprinting and the speed effect are
+	// accounted for. Movement exceeding it is reverted and reported to
+	// player.Handler.HandleFlag. If left as 0, the speed of players is not
+	// checked.
+	MaxMoveSpeed float64
+	// EventSink is the event.Sink that structured records of players
+	// joining, leaving, chatting and running commands are written to, so
+	// that an audit trail may be kept. If left as nil, records are discarded.
+	EventSink event.Sink
+	// Operators is a list of XUIDs of players that are operators of the
+	// Server. Players that are operators are given operator permissions when
+	// they join.
+	Operators []string
+	// OperatorsFile is the path to a JSON file holding a list of XUIDs of
+	// operators in addition to Operators. Operators added or removed at runtime
+	// using Server.AddOperator and Server.RemoveOperator are written to this
+	// file. If left empty, changes to operators are not persisted.
+	OperatorsFile string
 	// PlayerProvider is the player.Provider used for storing and loading player
 	// data. If left as nil, player data will be newly created every time a
 	// player joins the server and no data will be stored.
 	PlayerProvider player.Provider
+	// SpawnAtWorldSpawn specifies if players should always spawn at the spawn
+	// of the overworld when joining, rather than at the position saved in the
+	// PlayerProvider. Other data of players, such as their inventory, is
+	// still loaded.
+	SpawnAtWorldSpawn bool
 	// WorldProvider is the world.Provider used for storing and loading world
 	// data. If left as nil, world data will be newly created every time and
 	// chunks will always be newly generated when loaded. The world provider
@@ -81,6 +224,79 @@ type Config struct {
 	// ReadOnlyWorld specifies if the standard worlds should be read only. If
 	// set to true, the WorldProvider won't be saved to at all.
 	ReadOnlyWorld bool
+	// AutosaveInterval is the interval at which modified chunks of the
+	// standard worlds and the data of online players are saved, so that
+	// progress is not lost if the server crashes. If left as 0, data is only
+	// saved when chunks are unloaded, players leave or the server is closed.
+	AutosaveInterval time.Duration
+	// Difficulty is the difficulty that the standard worlds are set to when
+	// the Server is created. If left as nil, the difficulty stored in the
+	// worlds is used.
+	Difficulty world.Difficulty
+	// DisablePvP specifies if players in the standard worlds should be
+	// prevented from damaging each other when the Server is created. PvP may
+	// be toggled per world afterwards using world.World.SetPvP.
+	DisablePvP bool
+	// SpawnProtectionRadius is the radius in blocks around the spawn of the
+	// overworld in which players that are not operators cannot break or place
+	// blocks. If left as 0, the spawn is not protected.
+	SpawnProtectionRadius int
+	// ProtectBedrock specifies if players that are not operators are
+	// prevented from breaking and placing blocks in the lowest layer of the
+	// standard worlds, which generally consists of bedrock.
+	ProtectBedrock bool
+	// EntityMergeThreshold is the number of entities in a single chunk of the
+	// standard worlds above which dropped items merge with identical items
+	// anywhere in the chunk. If left as 0, items only merge with those close
+	// by.
+	EntityMergeThreshold int
+	// MaxChunkEntities is the maximum number of entities in a single chunk
+	// of the standard worlds. Entities other than players and items are not
+	// spawned in chunks holding this many entities, which protects against
+	// lag machines and accidental mass spawning of entities. If left as 0, the
+	// number of entities per chunk is not limited.
+	MaxChunkEntities int
+	// AntiXray specifies if ores are hidden from players in the standard
+	// worlds to counter x-ray clients. Ores that are not exposed to air or
+	// another transparent block are sent to players as the stone around them
+	// and only revealed once exposed or once a player comes close. Operators
+	// and players in creative mode always see the actual blocks.
+	AntiXray bool
+	// VoidLevel is the Y level in the overworld below which players are hurt
+	// by the void. If left as nil, the lowest Y level of the overworld is
+	// used. The void level may be changed per world using
+	// world.World.SetVoidLevel.
+	VoidLevel *int
+	// VoidTeleport specifies if players that fall into the void of any of
+	// the standard worlds should be teleported back to the spawn of that
+	// world instead of being hurt.
+	VoidTeleport bool
+	// EntityTrackingRange is the distance in blocks beyond which entities
+	// are removed from the clients of players, even if the chunk they are in
+	// is loaded, to reduce the amount of packets sent. If left as 0, all
+	// entities in chunks loaded by a player are shown to it.
+	EntityTrackingRange float64
+	// LowTPSThreshold is the amount of ticks per second below which a warning
+	// is logged that the Server is lagging behind. If left as 0, no warnings
+	// are logged.
+	LowTPSThreshold float64
+	// LowTPSChunksPerTick and LowTPSEntityTrackingRange replace the
+	// ChunksPerTick and EntityTrackingRange of all players while the TPS of
+	// the Server is below the LowTPSThreshold, to reduce the load on the
+	// Server until it recovers. If left as 0, the respective values are not
+	// changed when the Server is lagging behind.
+	LowTPSChunksPerTick       int
+	LowTPSEntityTrackingRange float64
+	// ChunkUnloadDelay is the duration that a chunk of one of the standard
+	// worlds must be out of view of all players before it is unloaded and
+	// saved. If left as 0, chunks are unloaded after 5 minutes.
+	ChunkUnloadDelay time.Duration
+	// OverworldRange is the building range of the overworld, which may be
+	// changed to create a world with an extended height. The minimum and the
+	// maximum plus one must be multiples of 16. The range is sent to players
+	// when they join, so that their client renders the world correctly. If
+	// left empty, the overworld has a building range of [-64, 320).
+	OverworldRange cube.Range
 	// Generator should return a function that specifies the world.Generator to
 	// use for every world.Dimension (world.Overworld, world.Nether and
 	// world.End). If left empty, Generator will be set to a flat world for each
@@ -97,6 +313,11 @@ type Config struct {
 	// may be added to the Server's worlds. If no entity types are registered,
 	// Entities will be set to entity.DefaultRegistry.
 	Entities world.EntityRegistry
+
+	// addresses holds the addresses that the Listeners listen on, if the Config
+	// was created using UserConfig.Config. Server.Reload uses it to find out
+	// if the addresses were changed.
+	addresses []string
 }
 
 // Logger is used to report information and errors from a dragonfly Server. Any
@@ -131,12 +352,32 @@ func (conf Config) New() *Server {
 	if conf.WorldProvider == nil {
 		conf.WorldProvider = world.NopProvider{}
 	}
+	if conf.EventSink == nil {
+		conf.EventSink = event.NopSink{}
+	}
 	if conf.Generator == nil {
 		conf.Generator = loadGenerator
 	}
+	if conf.LoginTimeout == 0 {
+		conf.LoginTimeout = time.Minute
+	}
 	if conf.MaxChunkRadius == 0 {
 		conf.MaxChunkRadius = 12
 	}
+	if r := clampChunkRadius(conf.MaxChunkRadius); r != conf.MaxChunkRadius {
+		conf.Log.Warnf("config: max chunk radius %v out of range, using %v", conf.MaxChunkRadius, r)
+		conf.MaxChunkRadius = r
+	}
+	if conf.ChunksPerTick <= 0 {
+		conf.ChunksPerTick = 4
+	}
+	if conf.MaxReach <= 0 {
+		conf.MaxReach = 8
+	}
+	if conf.ChatFormat == "" {
+		conf.ChatFormat = "<%v> %v"
+	}
+	conf.Messages = conf.Messages.withDefaults()
 	if len(conf.Entities.Types()) == 0 {
 		conf.Entities = entity.DefaultRegistry
 	}
@@ -147,16 +388,63 @@ func (conf Config) New() *Server {
 	}
 	// Copy resources so that the slice can't be edited afterwards.
 	conf.Resources = slices.Clone(conf.Resources)
+	if err := validateContentKeys(conf.Resources); err != nil {
+		conf.Log.Fatalf("config: %v", err)
+	}
+	if r := conf.OverworldRange; r != (cube.Range{}) && (r[0]%16 != 0 || (r[1]+1)%16 != 0 || r[0] >= r[1]) {
+		conf.Log.Fatalf("config: invalid overworld range %v: minimum and maximum + 1 must be multiples of 16", r)
+	}
+	proxies, err := parseTrustedProxies(conf.TrustedProxies)
+	if err != nil {
+		conf.Log.Fatalf("config: %v", err)
+	}
+
+	// Finalise the block registry before any worlds are created, so that the runtime IDs of custom blocks are
+	// assigned before chunks are loaded.
+	world_finaliseBlockRegistry()
 
 	srv := &Server{
 		conf:     conf,
 		incoming: make(chan *session.Session),
+		closing:  make(chan struct{}),
 		p:        make(map[uuid.UUID]*player.Player),
+		s:        make(map[uuid.UUID]*session.Session),
 		world:    &world.World{}, nether: &world.World{}, end: &world.World{},
+		limiter: &connLimiter{limit: conf.ConnectionsPerSecond},
+		proxies: proxies,
+	}
+	srv.pcond = sync.NewCond(&srv.pmu)
+	ops, err := loadOperators(conf.OperatorsFile, conf.Operators)
+	if err != nil {
+		conf.Log.Fatalf("load operators: %v", err)
+	}
+	srv.ops = ops
+	srv.name.Store(conf.Name)
+	if srv.conf.StatusProvider == nil {
+		srv.conf.StatusProvider = statusProvider{srv: srv}
 	}
 	srv.world = srv.createWorld(world.Overworld, &srv.nether, &srv.end)
 	srv.nether = srv.createWorld(world.Nether, &srv.world, &srv.end)
 	srv.end = srv.createWorld(world.End, &srv.nether, &srv.world)
+	if conf.Difficulty != nil {
+		for _, w := range []*world.World{srv.world, srv.nether, srv.end} {
+			w.SetDifficulty(conf.Difficulty)
+		}
+	}
+	if conf.DisablePvP {
+		for _, w := range []*world.World{srv.world, srv.nether, srv.end} {
+			w.SetPvP(false)
+		}
+	}
+	srv.world.SetSpawnProtection(conf.SpawnProtectionRadius)
+	for _, w := range []*world.World{srv.world, srv.nether, srv.end} {
+		w.SetBedrockProtection(conf.ProtectBedrock)
+		y, _ := w.VoidLevel()
+		if w == srv.world && conf.VoidLevel != nil {
+			y = *conf.VoidLevel
+		}
+		w.SetVoidLevel(y, conf.VoidTeleport)
+	}
 
 	srv.registerTargetFunc()
 	srv.checkNetIsolation()
@@ -174,6 +462,47 @@ type UserConfig struct {
 		// Address is the address on which the server should listen. Players may
 		// connect to this address in order to join.
 		Address string
+		// Addresses is a list of additional addresses on which the server
+		// listens, for example to listen on both IPv4 and IPv6 or on multiple
+		// ports. Players connecting to any of the addresses join the same
+		// server.
+		Addresses []string
+		// Compression is the compression algorithm used for packets sent over
+		// the network. It may be either "flate" or "snappy". Flate produces
+		// smaller packets, while snappy uses less CPU, which may be preferable
+		// on servers with limited processing power.
+		Compression string
+		// ConnectionsPerSecond is the maximum amount of connections accepted
+		// from a single IP address every second. If set to 0, the amount of
+		// connections is not limited.
+		ConnectionsPerSecond int
+		// LoginTimeout is the maximum duration in seconds that spawning a
+		// player may take after it has connected.
+		LoginTimeout int
+		// ReadTimeout is the duration in seconds after which players that
+		// have not sent any packets are disconnected. If set to 0, players are
+		// only disconnected once the underlying connection times out.
+		ReadTimeout int
+		// FlushRate is the interval in milliseconds at which packets sent to a
+		// player are batched and flushed. Higher values lead to better
+		// compression and lower CPU usage, but add latency. If set to 0,
+		// packets are flushed once every tick.
+		FlushRate int
+		// FlushImmediately specifies if packets sent to players are flushed
+		// as soon as they are written, ignoring FlushRate.
+		FlushImmediately bool
+		// MaxPacketViolations is the number of invalid packets a player may
+		// send before it is disconnected. If set to 0 or 1, players are
+		// disconnected on the first invalid packet.
+		MaxPacketViolations int
+		// StrictPackets specifies if players are disconnected immediately on
+		// any invalid or unexpected packet, regardless of
+		// MaxPacketViolations.
+		StrictPackets bool
+		// AcceptedVersions is a list of game versions, such as "1.20.10",
+		// that clients must have to join. If empty, all versions supported by
+		// the server are accepted.
+		AcceptedVersions []string
 	}
 	Server struct {
 		// Name is the name of the server as it shows up in the server list.
@@ -183,7 +512,9 @@ type UserConfig struct {
 		// away.
 		ShutdownMessage string
 		// AuthEnabled controls whether players must be connected to Xbox Live
-		// in order to join the server.
+		// in order to join the server. Disabling it allows players to join
+		// with any name and XUID, including those of operators, so it should
+		// only be disabled for development or behind an authenticating proxy.
 		AuthEnabled bool
 		// JoinMessage is the message that appears when a player joins the
 		// server. Leave this empty to disable it. %v is the placeholder for the
@@ -193,6 +524,55 @@ type UserConfig struct {
 		// server. Leave this empty to disable it. %v is the placeholder for the
 		// username of the player
 		QuitMessage string
+		// WelcomeMessage is the message sent only to a player joining the
+		// server once it has spawned. WelcomeTitle and WelcomeSubtitle are
+		// shown as a title to the player at the same time. Leave these empty
+		// to disable them. {name} is the placeholder for the username of the
+		// player and {online} for the number of players online.
+		WelcomeMessage, WelcomeTitle, WelcomeSubtitle string
+		// ChatFormat is the format of chat messages sent by players. The
+		// first %v is the placeholder for the username of the player and the
+		// second for the message.
+		ChatFormat string
+		// OperatorsFile is the path to a JSON file holding a list of XUIDs of
+		// players that are operators. Leave this empty to not persist
+		// operators.
+		OperatorsFile string
+		// EventLogFile is the path to a file that records of players joining,
+		// leaving, chatting and running commands are appended to as JSON
+		// lines. Leave this empty to not keep an event log.
+		EventLogFile string
+		// IdleTimeout is the duration in seconds after which players that
+		// have not moved or sent any input are kicked. If set to 0, idle
+		// players are never kicked.
+		IdleTimeout int
+		// RecoverPanics specifies if panics in the handling of connections
+		// and packets of players are recovered and logged, disconnecting only
+		// the player involved instead of crashing the server.
+		RecoverPanics bool
+		// LogLevel is the minimum level of messages that are logged. It must
+		// be either trace, debug, info, warning, error, fatal or panic. The
+		// debug level includes messages about individual connections.
+		LogLevel string
+		// LogFile is the path to a file that log messages are appended to, in
+		// addition to being written to the console. Leave this empty to not
+		// write log messages to a file.
+		LogFile string
+		// ChatCooldown limits the rate at which players may send chat
+		// messages.
+		ChatCooldown struct {
+			// Messages is the maximum amount of messages a player may send
+			// within Window seconds. If set to 0, the amount of messages is
+			// not limited.
+			Messages int
+			// Window is the duration in seconds of the window in which at most
+			// Messages messages may be sent.
+			Window float64
+			// BlockRepeated specifies if a message identical to the previous
+			// message of a player should be dropped if sent within Window
+			// seconds.
+			BlockRepeated bool
+		}
 	}
 	World struct {
 		// SaveData controls whether a world's data will be saved and loaded.
@@ -203,16 +583,84 @@ type UserConfig struct {
 		SaveData bool
 		// Folder is the folder that the data of the world resides in.
 		Folder string
+		// AutosaveInterval is the interval in seconds at which modified chunks
+		// and the data of online players are saved. If set to 0, data is only
+		// saved when chunks are unloaded, players leave or the server closes.
+		AutosaveInterval int
+		// Difficulty is the difficulty of the worlds. It must be either
+		// peaceful, easy, normal or hard.
+		Difficulty string
+		// PvP specifies if players in the worlds can damage each other.
+		PvP bool
+		// SpawnProtectionRadius is the radius in blocks around the spawn in
+		// which only operators can break and place blocks. If set to 0, the
+		// spawn is not protected.
+		SpawnProtectionRadius int
+		// ProtectBedrock specifies if only operators can break and place
+		// blocks in the lowest layer of the worlds.
+		ProtectBedrock bool
+		// EntityMergeThreshold is the number of entities in a chunk above
+		// which dropped items merge with identical items anywhere in the
+		// chunk. If set to 0, items only merge with those close by.
+		EntityMergeThreshold int
+		// MaxChunkEntities is the maximum number of entities in a chunk.
+		// Entities other than players and items are not spawned in full
+		// chunks. If set to 0, the number of entities per chunk is not
+		// limited.
+		MaxChunkEntities int
+		// AntiXray specifies if ores not exposed to air are hidden from
+		// players that are not operators or in creative mode, to counter
+		// x-ray clients.
+		AntiXray bool
+		// VoidDamageBelow is the Y level below which players are hurt by the
+		// void in the overworld. Set this to 0 to use the bottom of the world.
+		VoidDamageBelow int
+		// MinY and MaxY are the lowest and highest Y levels at which blocks
+		// may be placed in the overworld. Set both to 0 to use the default
+		// range of -64 to 319.
+		MinY, MaxY int
+		// VoidTeleport specifies if players that fall below the void level
+		// are teleported back to the spawn instead of being hurt.
+		VoidTeleport bool
+		// EntityTrackingRange is the distance in blocks beyond which entities
+		// are no longer shown to players. If set to 0, all entities in chunks
+		// loaded by a player are shown.
+		EntityTrackingRange float64
+		// LowTPSThreshold is the amount of ticks per second below which a
+		// warning is logged. If set to 0, no warnings are logged.
+		LowTPSThreshold float64
+		// LowTPSChunksPerTick is the amount of chunks sent to every player
+		// each tick while the server is below the LowTPSThreshold. If set to
+		// 0, chunk sending is not throttled.
+		LowTPSChunksPerTick int
+		// LowTPSEntityTrackingRange is the entity tracking range used while
+		// the server is below the LowTPSThreshold. If set to 0, the entity
+		// tracking range is not changed.
+		LowTPSEntityTrackingRange float64
 	}
 	Players struct {
 		// MaxCount is the maximum amount of players allowed to join the server
 		// at the same time. If set to 0, the amount of maximum players will
 		// grow every time a player joins.
 		MaxCount int
+		// QueueSize is the maximum amount of players that wait in a queue for
+		// a free slot when the server is full. If set to 0, players are
+		// refused when the server is full.
+		QueueSize int
 		// MaximumChunkRadius is the maximum chunk radius that players may set
 		// in their settings. If they try to set it above this number, it will
 		// be capped and set to the max.
 		MaximumChunkRadius int
+		// ChunksPerTick is the maximum amount of chunks sent to a player every
+		// tick. Lower values spread the chunks sent over more ticks.
+		ChunksPerTick int
+		// MaxReach is the maximum distance in blocks from which players may
+		// interact with blocks and entities when not in creative mode.
+		MaxReach float64
+		// MaxMoveSpeed is the maximum horizontal distance in blocks that
+		// players may move per tick at the default movement speed. If set to
+		// 0, the speed of players is not checked.
+		MaxMoveSpeed float64
 		// SaveData controls whether a player's data will be saved and loaded.
 		// If true, the server will use the default LevelDB data provider and if
 		// false, an empty provider will be used. To use your own provider, turn
@@ -222,6 +670,9 @@ type UserConfig struct {
 		// Folder controls where the player data will be stored by the default
 		// LevelDB player provider if it is enabled.
 		Folder string
+		// SpawnAtWorldSpawn specifies if players always spawn at the world
+		// spawn when joining, instead of at their last position.
+		SpawnAtWorldSpawn bool
 	}
 	Resources struct {
 		// AutoBuildPack is if the server should automatically generate a
@@ -233,25 +684,100 @@ type UserConfig struct {
 		// Required is a boolean to force the client to load the resource pack
 		// on join. If they do not accept, they'll have to leave the server.
 		Required bool
+		// ContentKeys maps the UUIDs of encrypted resource packs in Folder to
+		// the keys used to encrypt them. The keys are sent to the client so
+		// that it can decrypt the packs.
+		ContentKeys map[string]string
 	}
+	// Messages holds the messages shown to players disconnected by the
+	// server. Each message may also be a translation key, such as
+	// "disconnectionScreen.serverFull", which is translated by the client.
+	Messages Messages
 }
 
 // Config converts a UserConfig to a Config, so that it may be used for creating
-// a Server. An error is returned if creating data providers or loading
-// resources failed.
+// a Server. If log is nil, a logrus.Logger is created. The LogLevel and LogFile
+// of the UserConfig are applied if the Logger is a logrus.Logger. An error is
+// returned if creating data providers or loading resources failed.
 func (uc UserConfig) Config(log Logger) (Config, error) {
 	var err error
+	if log == nil {
+		log = logrus.New()
+	}
+	if l, ok := log.(*logrus.Logger); ok {
+		if err = uc.configureLogger(l); err != nil {
+			return Config{}, err
+		}
+	}
 	conf := Config{
-		Log:                     log,
-		Name:                    uc.Server.Name,
-		ResourcesRequired:       uc.Resources.Required,
-		AuthDisabled:            !uc.Server.AuthEnabled,
-		MaxPlayers:              uc.Players.MaxCount,
-		MaxChunkRadius:          uc.Players.MaximumChunkRadius,
-		JoinMessage:             uc.Server.JoinMessage,
-		QuitMessage:             uc.Server.QuitMessage,
-		ShutdownMessage:         uc.Server.ShutdownMessage,
-		DisableResourceBuilding: !uc.Resources.AutoBuildPack,
+		Log:                       log,
+		Name:                      uc.Server.Name,
+		ResourcesRequired:         uc.Resources.Required,
+		AuthDisabled:              !uc.Server.AuthEnabled,
+		MaxPlayers:                uc.Players.MaxCount,
+		QueueSize:                 uc.Players.QueueSize,
+		SpawnAtWorldSpawn:         uc.Players.SpawnAtWorldSpawn,
+		ConnectionsPerSecond:      uc.Network.ConnectionsPerSecond,
+		LoginTimeout:              time.Duration(uc.Network.LoginTimeout) * time.Second,
+		ReadTimeout:               time.Duration(uc.Network.ReadTimeout) * time.Second,
+		FlushImmediately:          uc.Network.FlushImmediately,
+		AcceptedVersions:          uc.Network.AcceptedVersions,
+		MaxChunkRadius:            uc.Players.MaximumChunkRadius,
+		ChunksPerTick:             uc.Players.ChunksPerTick,
+		MaxReach:                  uc.Players.MaxReach,
+		MaxMoveSpeed:              uc.Players.MaxMoveSpeed,
+		DisablePvP:                !uc.World.PvP,
+		SpawnProtectionRadius:     uc.World.SpawnProtectionRadius,
+		ProtectBedrock:            uc.World.ProtectBedrock,
+		EntityMergeThreshold:      uc.World.EntityMergeThreshold,
+		MaxChunkEntities:          uc.World.MaxChunkEntities,
+		AntiXray:                  uc.World.AntiXray,
+		VoidTeleport:              uc.World.VoidTeleport,
+		EntityTrackingRange:       uc.World.EntityTrackingRange,
+		JoinMessage:               uc.Server.JoinMessage,
+		QuitMessage:               uc.Server.QuitMessage,
+		WelcomeMessage:            uc.Server.WelcomeMessage,
+		WelcomeTitle:              uc.Server.WelcomeTitle,
+		WelcomeSubtitle:           uc.Server.WelcomeSubtitle,
+		ChatFormat:                uc.Server.ChatFormat,
+		ShutdownMessage:           uc.Server.ShutdownMessage,
+		DisableResourceBuilding:   !uc.Resources.AutoBuildPack,
+		AutosaveInterval:          time.Duration(uc.World.AutosaveInterval) * time.Second,
+		OperatorsFile:             uc.Server.OperatorsFile,
+		IdleTimeout:               time.Duration(uc.Server.IdleTimeout) * time.Second,
+		RecoverPanics:             uc.Server.RecoverPanics,
+		LowTPSThreshold:           uc.World.LowTPSThreshold,
+		LowTPSChunksPerTick:       uc.World.LowTPSChunksPerTick,
+		LowTPSEntityTrackingRange: uc.World.LowTPSEntityTrackingRange,
+		Messages:                  uc.Messages,
+		ChatCooldown: session.ChatCooldown{
+			Messages:      uc.Server.ChatCooldown.Messages,
+			Window:        time.Duration(uc.Server.ChatCooldown.Window * float64(time.Second)),
+			BlockRepeated: uc.Server.ChatCooldown.BlockRepeated,
+		},
+		PacketPolicy: session.PacketPolicy{
+			MaxViolations: uc.Network.MaxPacketViolations,
+			Strict:        uc.Network.StrictPackets,
+		},
+	}
+	if uc.World.MinY != 0 || uc.World.MaxY != 0 {
+		conf.OverworldRange = cube.Range{uc.World.MinY, uc.World.MaxY}
+	}
+	if y := uc.World.VoidDamageBelow; y != 0 {
+		conf.VoidLevel = &y
+	}
+	if _, err := uc.compression(); err != nil {
+		return conf, err
+	}
+	if conf.Difficulty, err = uc.difficulty(); err != nil {
+		return conf, err
+	}
+	if uc.Server.EventLogFile != "" {
+		f, err := os.OpenFile(uc.Server.EventLogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return conf, fmt.Errorf("open event log file: %w", err)
+		}
+		conf.EventSink = event.NewJSONSink(f)
 	}
 	if uc.World.SaveData {
 		conf.WorldProvider, err = mcdb.New(log, uc.World.Folder, opt.FlateCompression)
@@ -259,7 +785,7 @@ func (uc UserConfig) Config(log Logger) (Config, error) {
 			return conf, fmt.Errorf("create world provider: %w", err)
 		}
 	}
-	conf.Resources, err = loadResources(uc.Resources.Folder)
+	conf.Resources, err = loadResources(uc.Resources.Folder, uc.Resources.ContentKeys)
 	if err != nil {
 		return conf, fmt.Errorf("load resources: %w", err)
 	}
@@ -269,12 +795,17 @@ func (uc UserConfig) Config(log Logger) (Config, error) {
 			return conf, fmt.Errorf("create player provider: %w", err)
 		}
 	}
-	conf.Listeners = append(conf.Listeners, uc.listenerFunc)
+	conf.addresses = append([]string{uc.Network.Address}, uc.Network.Addresses...)
+	for _, addr := range conf.addresses {
+		conf.Listeners = append(conf.Listeners, uc.listenerFunc(addr))
+	}
 	return conf, nil
 }
 
-// loadResources loads all resource packs found in a directory passed.
-func loadResources(dir string) ([]*resource.Pack, error) {
+// loadResources loads all resource packs found in a directory passed. Packs
+// with a UUID present in keys are given the content key in the map, which
+// must be 32 bytes long.
+func loadResources(dir string, keys map[string]string) ([]*resource.Pack, error) {
 	_ = os.MkdirAll(dir, 0777)
 
 	resources, err := os.ReadDir(dir)
@@ -287,10 +818,32 @@ func loadResources(dir string) ([]*resource.Pack, error) {
 		if err != nil {
 			return nil, fmt.Errorf("compile resource (%v): %w", entry.Name(), err)
 		}
+		if key, ok := keys[packs[i].UUID()]; ok {
+			packs[i] = packs[i].WithContentKey(key)
+		}
+	}
+	for id := range keys {
+		if !slices.ContainsFunc(packs, func(pack *resource.Pack) bool { return pack.UUID() == id }) {
+			return nil, fmt.Errorf("content key for resource pack %v: no such pack in %v", id, dir)
+		}
+	}
+	if err := validateContentKeys(packs); err != nil {
+		return nil, err
 	}
 	return packs, nil
 }
 
+// validateContentKeys checks if the content keys of all encrypted packs passed
+// are valid AES-256 keys, so that they can be decrypted by the client.
+func validateContentKeys(packs []*resource.Pack) error {
+	for _, pack := range packs {
+		if pack.Encrypted() && len(pack.ContentKey()) != 32 {
+			return fmt.Errorf("content key for resource pack %v (%v): must be 32 bytes long, got %v", pack.Name(), pack.UUID(), len(pack.ContentKey()))
+		}
+	}
+	return nil
+}
+
 // loadGenerator loads a standard world.Generator for a world.Dimension.
 func loadGenerator(dim world.Dimension) world.Generator {
 	switch dim {
@@ -304,18 +857,78 @@ func loadGenerator(dim world.Dimension) world.Generator {
 	panic("should never happen")
 }
 
+// hiddenBlocks returns the blocks hidden from players in a world.Dimension if
+// AntiXray is enabled, mapped to the blocks they are replaced with.
+func hiddenBlocks(dim world.Dimension) map[world.Block]world.Block {
+	switch dim {
+	case world.Overworld:
+		m := make(map[world.Block]world.Block)
+		for _, t := range block.OreTypes() {
+			replacement := world.Block(block.Stone{})
+			if t == block.DeepslateOre() {
+				replacement = block.Deepslate{Type: block.NormalDeepslate()}
+			}
+			for _, ore := range []world.Block{block.CoalOre{Type: t}, block.CopperOre{Type: t}, block.IronOre{Type: t}, block.GoldOre{Type: t}, block.LapisOre{Type: t}, block.DiamondOre{Type: t}, block.EmeraldOre{Type: t}} {
+				m[ore] = replacement
+			}
+		}
+		return m
+	case world.Nether:
+		return map[world.Block]world.Block{
+			block.NetherQuartzOre{}: block.Netherrack{},
+			block.NetherGoldOre{}:   block.Netherrack{},
+			block.AncientDebris{}:   block.Netherrack{},
+		}
+	}
+	return nil
+}
+
+// difficulty returns the world.Difficulty set in the UserConfig.
+func (uc UserConfig) difficulty() (world.Difficulty, error) {
+	switch strings.ToLower(uc.World.Difficulty) {
+	case "peaceful":
+		return world.DifficultyPeaceful, nil
+	case "easy":
+		return world.DifficultyEasy, nil
+	case "", "normal":
+		return world.DifficultyNormal, nil
+	case "hard":
+		return world.DifficultyHard, nil
+	}
+	return nil, fmt.Errorf("unknown difficulty %q: must be either peaceful, easy, normal or hard", uc.World.Difficulty)
+}
+
 // DefaultConfig returns a configuration with the default values filled out.
 func DefaultConfig() UserConfig {
 	c := UserConfig{}
 	c.Network.Address = ":19132"
+	c.Network.Compression = "flate"
+	c.Network.FlushRate = 0
+	c.Network.ConnectionsPerSecond = 5
+	c.Network.LoginTimeout = 60
+	c.Network.ReadTimeout = 20
 	c.Server.Name = "Dragonfly Server"
 	c.Server.ShutdownMessage = "Server closed."
 	c.Server.AuthEnabled = true
 	c.Server.JoinMessage = "%v has joined the game"
 	c.Server.QuitMessage = "%v has left the game"
+	c.Server.ChatFormat = "<%v> %v"
+	c.Messages = DefaultMessages()
+	c.Server.OperatorsFile = "operators.json"
+	c.Server.LogLevel = "debug"
+	c.Server.ChatCooldown.Messages = 5
+	c.Server.ChatCooldown.Window = 5
+	c.Server.ChatCooldown.BlockRepeated = true
 	c.World.SaveData = true
 	c.World.Folder = "world"
+	c.World.AutosaveInterval = 300
+	c.World.Difficulty = "normal"
+	c.World.PvP = true
+	c.World.SpawnProtectionRadius = 0
+	c.World.LowTPSThreshold = 15
 	c.Players.MaximumChunkRadius = 32
+	c.Players.ChunksPerTick = 4
+	c.Players.MaxReach = 8
 	c.Players.SaveData = true
 	c.Players.Folder = "players"
 	c.Resources.AutoBuildPack = true
@@ -323,3 +936,15 @@ func DefaultConfig() UserConfig {
 	c.Resources.Required = false
 	return c
 }
+
+// clampChunkRadius limits the maximum chunk radius r passed to a value between
+// 1 and 32.
+func clampChunkRadius(r int) int {
+	if r < 1 {
+		return 1
+	}
+	if r > 32 {
+		return 32
+	}
+	return r
+}
@@ -77,6 +77,9 @@ func (it *Item) Tick(w *world.World, current int64) {
 		return
 	}
 
+	if current%20 == 0 && w.Crowded(m.pos) && it.mergeCrowded(w, m.pos) {
+		return
+	}
 	if it.pickupDelay == 0 {
 		it.checkNearby(w, m.pos)
 	} else if it.pickupDelay != math.MaxInt16 {
@@ -110,6 +113,20 @@ func (it *Item) checkNearby(w *world.World, pos mgl64.Vec3) {
 	}
 }
 
+// mergeCrowded attempts to merge the item entity with any other item entity in the same chunk, regardless of
+// the distance between the two. It is called for chunks that hold too many entities, to reduce the number of
+// item entities in them.
+func (it *Item) mergeCrowded(w *world.World, pos mgl64.Vec3) bool {
+	chunkMin := mgl64.Vec3{math.Floor(pos[0]/16) * 16, float64(w.Range()[0]), math.Floor(pos[2]/16) * 16}
+	chunkMax := chunkMin.Add(mgl64.Vec3{16, float64(w.Range().Height()), 16})
+	for _, e := range w.EntitiesWithin(cube.Box(chunkMin[0], chunkMin[1], chunkMin[2], chunkMax[0], chunkMax[1], chunkMax[2]), nil) {
+		if other, ok := e.(*Item); ok && other != it && it.merge(w, other, pos) {
+			return true
+		}
+	}
+	return false
+}
+
 // merge merges the item entity with another item entity.
 func (it *Item) merge(w *world.World, other *Item, pos mgl64.Vec3) bool {
 	if other.i.Count() == other.i.MaxCount() || it.i.Count() == it.i.MaxCount() {
@@ -121,6 +138,10 @@ func (it *Item) merge(w *world.World, other *Item, pos mgl64.Vec3) bool {
 	}
 
 	a, b := other.i.AddStack(it.i)
+	// Close the item entities before adding the new ones, so that they do not count towards the maximum
+	// number of entities in the chunk.
+	_ = it.Close()
+	_ = other.Close()
 
 	newA := NewItem(a, other.Position())
 	newA.SetVelocity(other.Velocity())
@@ -131,8 +152,6 @@ func (it *Item) merge(w *world.World, other *Item, pos mgl64.Vec3) bool {
 		newB.SetVelocity(it.vel)
 		w.AddEntity(newB)
 	}
-	_ = it.Close()
-	_ = other.Close()
 	return true
 }
 
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+var (
+	// ErrAlreadyStarted is returned by Server.Start if the Server was already
+	// started before.
+	ErrAlreadyStarted = errors.New("server already started")
+	// ErrServerClosed is returned by Server.Close if the Server was already
+	// closed before.
+	ErrServerClosed = errors.New("server closed")
+	// ErrNotStarted is returned by Server.Close if the Server was not started
+	// yet, or if starting it failed.
+	ErrNotStarted = errors.New("server not started")
+	// ErrAddressInUse is matched by a ListenError returned by Server.Start if
+	// the address of one of its Listeners is already in use, for example by
+	// another server running on the same port. It may be checked for using
+	// errors.Is.
+	ErrAddressInUse = errors.New("address already in use")
+	// ErrEmptyXUID is returned by Server.AddOperator if the XUID passed is
+	// empty. Players that are not authenticated have an empty XUID and can
+	// therefore not be made operator.
+	ErrEmptyXUID = errors.New("empty XUID")
+)
+
+// ListenError is returned by Server.Start if one of the Listeners of the
+// Server could not be created, for example because its address is already in
+// use. The underlying error may be inspected using errors.Is and errors.As.
+type ListenError struct {
+	// Err is the error returned by the listener function of the Config.
+	Err error
+}
+
+// Error ...
+func (err ListenError) Error() string {
+	return fmt.Sprintf("create listener: %v", err.Err)
+}
+
+// Is reports if the ListenError matches the target passed. A ListenError
+// matches ErrAddressInUse if its underlying error is syscall.EADDRINUSE.
+func (err ListenError) Is(target error) bool {
+	return target == ErrAddressInUse && errors.Is(err.Err, syscall.EADDRINUSE)
+}
+
+// Unwrap returns the underlying error of the ListenError.
+func (err ListenError) Unwrap() error {
+	return err.Err
+}
@@ -1,6 +1,9 @@
-// Package event exposes a single exported `Context` type that may be used to influence the execution flow of events
-// that occur on a server.
+// Package event exposes a `Context` type that may be used to influence the execution flow of events that occur on a
+// server.
 // Generally, the caller of `event.C()` calls `Context.Cancelled()` to check if the `Context` was cancelled (using
 // `Context.Cancel()`) by whatever code it was passed to.
 // who is then able to cancel it by calling `Context.Cancel()`.
+//
+// Additionally, the package exposes a `Sink` interface that structured `Record`s of events, such as players joining
+// or chatting, are written to, so that an audit trail may be kept. `JSONSink` writes these records as JSON lines.
 package event
@@ -0,0 +1,71 @@
+package event
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Sink is a destination for structured Records of events that occur on a server, such as players joining, chatting
+// and running commands. A Sink may be used to keep an audit trail in a machine-readable form.
+// Implementations of Sink must be safe for concurrent usage.
+type Sink interface {
+	// Write writes a Record to the Sink. Any errors that occur while writing are handled by the Sink itself.
+	Write(r Record)
+}
+
+// RecordType is the type of event that a Record describes.
+type RecordType string
+
+const (
+	// RecordJoin is the RecordType of a player joining the server.
+	RecordJoin RecordType = "join"
+	// RecordQuit is the RecordType of a player leaving the server. The Reason of the Record holds why the player
+	// left, for example because it was kicked.
+	RecordQuit RecordType = "quit"
+	// RecordChat is the RecordType of a player sending a chat message. The Message of the Record holds the message.
+	RecordChat RecordType = "chat"
+	// RecordCommand is the RecordType of a player running a command. The Message of the Record holds the full
+	// command line.
+	RecordCommand RecordType = "command"
+)
+
+// Record is a structured description of a single event that occurred on a server.
+type Record struct {
+	// Time is the time at which the event occurred.
+	Time time.Time `json:"time"`
+	// Type is the type of the event.
+	Type RecordType `json:"type"`
+	// Name and XUID are the name and XUID of the player that caused the event.
+	Name string `json:"name"`
+	XUID string `json:"xuid,omitempty"`
+	// Message is the chat message or command line of the event, if any.
+	Message string `json:"message,omitempty"`
+	// Reason is the reason of the event, if any, such as the reason a player left the server.
+	Reason string `json:"reason,omitempty"`
+}
+
+// NopSink is a Sink that discards all Records written to it.
+type NopSink struct{}
+
+// Write ...
+func (NopSink) Write(Record) {}
+
+// JSONSink is a Sink that writes Records to an io.Writer as JSON, one Record per line.
+type JSONSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONSink returns a JSONSink that writes Records to the io.Writer passed.
+func NewJSONSink(w io.Writer) *JSONSink {
+	return &JSONSink{enc: json.NewEncoder(w)}
+}
+
+// Write writes the Record passed to the underlying io.Writer, followed by a newline.
+func (s *JSONSink) Write(r Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.enc.Encode(r)
+}
@@ -22,6 +22,17 @@ type Inventory struct {
 
 	f      func(slot int, before, after item.Stack)
 	canAdd func(s item.Stack, slot int) bool
+
+	viewerMu sync.RWMutex
+	viewers  map[Viewer]struct{}
+}
+
+// Viewer is a viewer of an Inventory. It is updated whenever a slot of the Inventory it was added to using
+// Inventory.AddViewer changes.
+type Viewer interface {
+	// ViewSlotChange views a change of a single slot in the inventory, in which the item was changed to the
+	// new item passed.
+	ViewSlotChange(slot int, newItem item.Stack)
 }
 
 // ErrSlotOutOfRange is returned by any methods on inventory when a slot is passed which is not within the
@@ -292,6 +303,23 @@ func (inv *Inventory) Clear() []item.Stack {
 	return items
 }
 
+// AddViewer adds a Viewer to the Inventory, so that it is updated whenever a slot of the Inventory changes.
+func (inv *Inventory) AddViewer(v Viewer) {
+	inv.viewerMu.Lock()
+	defer inv.viewerMu.Unlock()
+	if inv.viewers == nil {
+		inv.viewers = make(map[Viewer]struct{})
+	}
+	inv.viewers[v] = struct{}{}
+}
+
+// RemoveViewer removes a Viewer from the Inventory, so that it is no longer updated when a slot changes.
+func (inv *Inventory) RemoveViewer(v Viewer) {
+	inv.viewerMu.Lock()
+	defer inv.viewerMu.Unlock()
+	delete(inv.viewers, v)
+}
+
 // Handle assigns a Handler to an Inventory so that its methods are called for the respective events. Nil may be passed
 // to set the default NopHandler.
 func (inv *Inventory) Handle(h Handler) {
@@ -327,6 +355,12 @@ func (inv *Inventory) setItem(slot int, it item.Stack) func() {
 	inv.slots[slot] = it
 	return func() {
 		inv.f(slot, before, it)
+
+		inv.viewerMu.RLock()
+		defer inv.viewerMu.RUnlock()
+		for v := range inv.viewers {
+			v.ViewSlotChange(slot, it)
+		}
 	}
 }
 
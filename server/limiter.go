@@ -0,0 +1,41 @@
+package server
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// connLimiter limits the amount of connections accepted from a single IP
+// address every second.
+type connLimiter struct {
+	// limit is the maximum amount of connections accepted per IP address every
+	// second. If limit is 0 or lower, all connections are accepted.
+	limit int
+
+	mu       sync.Mutex
+	second   int64
+	attempts map[string]int
+}
+
+// allow registers a connection attempt from the net.Addr passed and returns
+// true if the connection should be accepted.
+func (l *connLimiter) allow(addr net.Addr) bool {
+	if l.limit <= 0 {
+		return true
+	}
+	ip := addr.String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now := time.Now().Unix(); now != l.second || l.attempts == nil {
+		// A new second started, so the attempts of the previous second no
+		// longer count.
+		l.second, l.attempts = now, make(map[string]int)
+	}
+	l.attempts[ip]++
+	return l.attempts[ip] <= l.limit
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"github.com/df-mc/dragonfly/server/session"
 	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 	"io"
+	"strings"
+	"time"
 )
 
 // Listener is a source for connections that may be listened on by a Server using Server.listen. Proxies can use this to
@@ -18,18 +21,38 @@ type Listener interface {
 	io.Closer
 }
 
-// listenerFunc may be used to return a *minecraft.Listener using a Config. It
-// is the standard listener used when UserConfig.Config() is called.
-func (uc UserConfig) listenerFunc(conf Config) (Listener, error) {
+// listenerFunc returns a function that may be used to return a
+// *minecraft.Listener listening on the address passed using a Config. It is
+// the standard listener used when UserConfig.Config() is called.
+func (uc UserConfig) listenerFunc(address string) func(conf Config) (Listener, error) {
+	return func(conf Config) (Listener, error) {
+		return uc.listen(conf, address)
+	}
+}
+
+// listen creates a *minecraft.Listener listening on the address passed using
+// the Config and the network settings of the UserConfig.
+func (uc UserConfig) listen(conf Config, address string) (Listener, error) {
+	compression, err := uc.compression()
+	if err != nil {
+		return nil, err
+	}
+	// A negative flush rate disables flushing by the connection itself, which is then left to the Session:
+	// Either once every tick or directly after writing a packet.
+	flushRate := time.Duration(uc.Network.FlushRate) * time.Millisecond
+	if flushRate <= 0 || uc.Network.FlushImmediately {
+		flushRate = -1
+	}
 	cfg := minecraft.ListenConfig{
-		MaximumPlayers:         conf.MaxPlayers,
-		StatusProvider:         statusProvider{name: conf.Name},
+		StatusProvider:         conf.StatusProvider,
 		AuthenticationDisabled: conf.AuthDisabled,
 		ResourcePacks:          conf.Resources,
 		Biomes:                 biomes(),
 		TexturePacksRequired:   conf.ResourcesRequired,
+		Compression:            compression,
+		FlushRate:              flushRate,
 	}
-	l, err := cfg.Listen("raknet", uc.Network.Address)
+	l, err := cfg.Listen("raknet", address)
 	if err != nil {
 		return nil, fmt.Errorf("create minecraft listener: %w", err)
 	}
@@ -37,6 +60,18 @@ func (uc UserConfig) listenerFunc(conf Config) (Listener, error) {
 	return listener{l}, nil
 }
 
+// compression returns the packet.Compression set in the network settings of
+// the UserConfig. An error is returned if the compression is unknown.
+func (uc UserConfig) compression() (packet.Compression, error) {
+	switch strings.ToLower(uc.Network.Compression) {
+	case "", "flate":
+		return packet.FlateCompression{}, nil
+	case "snappy":
+		return packet.SnappyCompression{}, nil
+	}
+	return nil, fmt.Errorf("unknown network compression %q: must be either flate or snappy", uc.Network.Compression)
+}
+
 // listener is a Listener implementation that wraps around a minecraft.Listener so that it can be listened on by
 // Server.
 type listener struct {
@@ -55,5 +90,8 @@ func (l listener) Accept() (session.Conn, error) {
 
 // Disconnect disconnects a connection from the Listener with a reason.
 func (l listener) Disconnect(conn session.Conn, reason string) error {
+	if pc, ok := conn.(proxyConn); ok {
+		conn = pc.Conn
+	}
 	return l.Listener.Disconnect(conn.(*minecraft.Conn), reason)
 }
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+)
+
+// fileHook is a logrus.Hook that writes all entries logged to a file, without
+// colours.
+type fileHook struct {
+	w         io.Writer
+	formatter logrus.Formatter
+}
+
+// newFileHook opens the file at the path passed for appending and returns a
+// fileHook that writes to it.
+func newFileHook(path string) (*fileHook, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open log file: %w", err)
+	}
+	return &fileHook{w: f, formatter: &logrus.TextFormatter{DisableColors: true, FullTimestamp: true}}, nil
+}
+
+// Levels returns all logrus levels, so that every entry is written to the file.
+func (h *fileHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire formats the entry passed and writes it to the file.
+func (h *fileHook) Fire(e *logrus.Entry) error {
+	b, err := h.formatter.Format(e)
+	if err != nil {
+		return err
+	}
+	_, err = h.w.Write(b)
+	return err
+}
+
+// configureLogger sets the level of the logrus.Logger passed to the LogLevel
+// of the UserConfig and makes it write to the LogFile, if set.
+func (uc UserConfig) configureLogger(log *logrus.Logger) error {
+	if uc.Server.LogLevel != "" {
+		lvl, err := logrus.ParseLevel(uc.Server.LogLevel)
+		if err != nil {
+			return fmt.Errorf("parse log level: %w", err)
+		}
+		log.SetLevel(lvl)
+	}
+	if uc.Server.LogFile != "" {
+		h, err := newFileHook(uc.Server.LogFile)
+		if err != nil {
+			return err
+		}
+		log.AddHook(h)
+	}
+	return nil
+}
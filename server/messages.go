@@ -0,0 +1,65 @@
+package server
+
+// Messages holds the messages shown to players when they are disconnected by
+// the Server. Each message may either be a literal message or a translation
+// key known to the client, such as "disconnectionScreen.serverFull", in which
+// case every client shows the message in its own language. Fields left empty
+// are filled with the message returned by DefaultMessages.
+type Messages struct {
+	// TooManyConnections is shown to connections refused because too many
+	// connection attempts were made from their address.
+	TooManyConnections string
+	// UnsupportedVersion is shown to players whose game version is not in the
+	// AcceptedVersions of the Config. It may have two '%v' arguments, which are
+	// replaced with the version of the player and the accepted versions.
+	UnsupportedVersion string
+	// AlreadyLoggedIn is shown to players joining while a player with the same
+	// UUID is online and RejectDuplicateLogins is set.
+	AlreadyLoggedIn string
+	// LoggedInElsewhere is shown to players that are disconnected because a
+	// player with the same UUID joined the Server.
+	LoggedInElsewhere string
+	// LoginTimeout is shown to players that failed to spawn within the
+	// LoginTimeout of the Config.
+	LoginTimeout string
+	// Idle is shown to players kicked for being idle for longer than the
+	// IdleTimeout of the Config.
+	Idle string
+}
+
+// DefaultMessages returns the Messages used by the Server by default.
+func DefaultMessages() Messages {
+	return Messages{
+		TooManyConnections: "Too many connection attempts. Please try again later.",
+		UnsupportedVersion: "Your game version (%v) is not supported. Please use one of: %v.",
+		AlreadyLoggedIn:    "Already logged in.",
+		LoggedInElsewhere:  "Logged in from another location.",
+		LoginTimeout:       "Connection timeout.",
+		Idle:               "You have been kicked for being idle.",
+	}
+}
+
+// withDefaults returns a copy of the Messages with all empty fields replaced
+// by the respective message returned by DefaultMessages.
+func (m Messages) withDefaults() Messages {
+	def := DefaultMessages()
+	if m.TooManyConnections == "" {
+		m.TooManyConnections = def.TooManyConnections
+	}
+	if m.UnsupportedVersion == "" {
+		m.UnsupportedVersion = def.UnsupportedVersion
+	}
+	if m.AlreadyLoggedIn == "" {
+		m.AlreadyLoggedIn = def.AlreadyLoggedIn
+	}
+	if m.LoggedInElsewhere == "" {
+		m.LoggedInElsewhere = def.LoggedInElsewhere
+	}
+	if m.LoginTimeout == "" {
+		m.LoginTimeout = def.LoginTimeout
+	}
+	if m.Idle == "" {
+		m.Idle = def.Idle
+	}
+	return m
+}
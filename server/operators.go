@@ -0,0 +1,99 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"os"
+	"sync"
+)
+
+// operatorList holds the XUIDs of all operators of a Server. If a file is set,
+// changes to the list are written to it.
+type operatorList struct {
+	mu   sync.Mutex
+	file string
+	xuid map[string]struct{}
+}
+
+// loadOperators loads the operatorList stored in the JSON file passed and adds
+// the XUIDs passed to it. If file is empty, the list is not persisted. An error
+// is returned if the file exists but could not be read.
+func loadOperators(file string, xuids []string) (*operatorList, error) {
+	l := &operatorList{file: file, xuid: make(map[string]struct{})}
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("read operators file: %w", err)
+		}
+		var stored []string
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &stored); err != nil {
+				return nil, fmt.Errorf("decode operators file: %w", err)
+			}
+		}
+		xuids = append(stored, xuids...)
+	}
+	for _, xuid := range xuids {
+		if xuid != "" {
+			l.xuid[xuid] = struct{}{}
+		}
+	}
+	return l, nil
+}
+
+// add adds an XUID to the operatorList and saves the list. Empty XUIDs, as
+// held by players that are not authenticated, are ignored.
+func (l *operatorList) add(xuid string) error {
+	if xuid == "" {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.xuid[xuid] = struct{}{}
+	return l.save()
+}
+
+// remove removes an XUID from the operatorList and saves the list.
+func (l *operatorList) remove(xuid string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.xuid, xuid)
+	return l.save()
+}
+
+// contains checks if the XUID passed is in the operatorList.
+func (l *operatorList) contains(xuid string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.xuid[xuid]
+	return ok && xuid != ""
+}
+
+// list returns a sorted list of all XUIDs in the operatorList.
+func (l *operatorList) list() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	xuids := maps.Keys(l.xuid)
+	slices.Sort(xuids)
+	return xuids
+}
+
+// save writes the operatorList to its file, if one is set. save must only be
+// called while the operatorList is locked.
+func (l *operatorList) save() error {
+	if l.file == "" {
+		return nil
+	}
+	xuids := maps.Keys(l.xuid)
+	slices.Sort(xuids)
+	data, err := json.MarshalIndent(xuids, "", "\t")
+	if err != nil {
+		return fmt.Errorf("encode operators: %w", err)
+	}
+	if err := os.WriteFile(l.file, data, 0644); err != nil {
+		return fmt.Errorf("write operators file: %w", err)
+	}
+	return nil
+}
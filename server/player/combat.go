@@ -0,0 +1,60 @@
+package player
+
+import (
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/world"
+	"time"
+)
+
+// damageRecord holds a world.DamageSource along with the time at which the damage was dealt.
+type damageRecord struct {
+	src world.DamageSource
+	at  time.Time
+}
+
+// LastDamageSource returns the world.DamageSource of the last damage dealt to the player and the time at which
+// it was dealt. False is returned if the player was not hurt since it joined or last respawned.
+func (p *Player) LastDamageSource() (world.DamageSource, time.Time, bool) {
+	r := p.lastDamage.Load()
+	return r.src, r.at, r.src != nil
+}
+
+// LastAttacker returns the entity that last hurt the player, either directly or using a projectile, if this
+// happened within the window passed. It may be used to tag players in combat or to credit kills for deaths that
+// were not directly caused by an attack, such as falling after being knocked off a ledge.
+func (p *Player) LastAttacker(window time.Duration) (world.Entity, bool) {
+	r := p.lastAttack.Load()
+	if r.src == nil || time.Since(r.at) > window {
+		return nil, false
+	}
+	return damageAttacker(r.src), true
+}
+
+// recordDamage records the world.DamageSource passed as the last damage dealt to the player, and as the last
+// attack if it was dealt by another entity.
+func (p *Player) recordDamage(src world.DamageSource) {
+	r := damageRecord{src: src, at: time.Now()}
+	p.lastDamage.Store(r)
+	if damageAttacker(src) != nil {
+		p.lastAttack.Store(r)
+	}
+}
+
+// damageAttacker returns the entity responsible for the world.DamageSource passed if it was an attack, either
+// directly or using a projectile. Nil is returned for other damage sources.
+func damageAttacker(src world.DamageSource) world.Entity {
+	switch s := src.(type) {
+	case entity.AttackDamageSource:
+		return s.Attacker
+	case entity.ProjectileDamageSource:
+		return s.Owner
+	}
+	return nil
+}
+
+// attackedByPlayer checks if the world.DamageSource passed is an attack by a player, either directly or using
+// a projectile.
+func attackedByPlayer(src world.DamageSource) bool {
+	_, ok := damageAttacker(src).(*Player)
+	return ok
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/google/uuid"
+	"time"
 )
 
 // Data is a struct that contains all the data of that player to be passed on to the Provider and saved.
@@ -56,6 +57,10 @@ type Data struct {
 	FallDistance float64
 	// World is the world the player was last in.
 	World *world.World
+	// Playtime is the total duration that the player has played on the server.
+	Playtime time.Duration
+	// LastPlayed is the time at which the player was last online, which is the time the Data was last saved.
+	LastPlayed time.Time
 }
 
 // InventoryData is a struct that contains all data of the player inventories.
@@ -0,0 +1,98 @@
+package player
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/entity"
+	"github.com/df-mc/dragonfly/server/entity/effect"
+	"github.com/df-mc/dragonfly/server/item/enchantment"
+	"github.com/df-mc/dragonfly/server/world"
+	"strings"
+)
+
+// deathMessage returns the default message broadcast when a player with the name passed dies to the
+// world.DamageSource passed. The message is returned both in English, such as 'Steve was slain by Alex', and
+// as a Bedrock Edition translation key with its parameters, so that clients show it in their own language.
+func deathMessage(name string, src world.DamageSource) (msg, key string, params []string) {
+	key, format, killer := deathTranslation(src)
+	if killer == nil {
+		return fmt.Sprintf(format, name), key, []string{name}
+	}
+	return fmt.Sprintf(format, name, entityName(killer)), key, []string{name, entityParameter(killer)}
+}
+
+// deathTranslation returns the translation key and English format of the death message for the
+// world.DamageSource passed. If the message names the entity that killed the player, it is returned too.
+func deathTranslation(src world.DamageSource) (key, format string, killer world.Entity) {
+	switch s := src.(type) {
+	case entity.AttackDamageSource:
+		if _, ok := s.Attacker.(*Player); ok {
+			return "death.attack.player", "%v was slain by %v", s.Attacker
+		}
+		return "death.attack.mob", "%v was slain by %v", s.Attacker
+	case entity.ProjectileDamageSource:
+		if s.Owner != nil {
+			return "death.attack.arrow", "%v was shot by %v", s.Owner
+		}
+	case enchantment.ThornsDamageSource:
+		return "death.attack.thorns", "%v was killed trying to hurt %v", s.Owner
+	case entity.VoidDamageSource:
+		return "death.attack.outOfWorld", "%v fell out of the world", nil
+	case entity.SuffocationDamageSource:
+		return "death.attack.inWall", "%v suffocated in a wall", nil
+	case entity.DrowningDamageSource:
+		return "death.attack.drown", "%v drowned", nil
+	case entity.FallDamageSource:
+		return "death.fell.accident.generic", "%v fell from a high place", nil
+	case entity.GlideDamageSource:
+		return "death.attack.flyIntoWall", "%v experienced kinetic energy", nil
+	case entity.LightningDamageSource:
+		return "death.attack.lightningBolt", "%v was struck by lightning", nil
+	case entity.ExplosionDamageSource:
+		return "death.attack.explosion", "%v blew up", nil
+	case block.FireDamageSource:
+		return "death.attack.onFire", "%v burned to death", nil
+	case block.LavaDamageSource:
+		return "death.attack.lava", "%v tried to swim in lava", nil
+	case block.DamageSource:
+		if _, ok := s.Block.(block.Cactus); ok {
+			return "death.attack.cactus", "%v was pricked to death", nil
+		}
+	case effect.WitherDamageSource:
+		return "death.attack.wither", "%v withered away", nil
+	case effect.InstantDamageSource:
+		return "death.attack.magic", "%v was killed by magic", nil
+	case StarvationDamageSource:
+		return "death.attack.starve", "%v starved to death", nil
+	}
+	return "death.attack.generic", "%v died", nil
+}
+
+// entityName returns a readable name of the world.Entity passed for use in death messages. For players, this is
+// the name of the player. For other entities, the name tag is used if it is set, or the name of the entity type
+// otherwise.
+func entityName(e world.Entity) string {
+	switch e := e.(type) {
+	case *Player:
+		return e.Name()
+	case interface{ NameTag() string }:
+		if tag := e.NameTag(); tag != "" {
+			return tag
+		}
+	}
+	return strings.ReplaceAll(strings.TrimPrefix(e.Type().EncodeEntity(), "minecraft:"), "_", " ")
+}
+
+// entityParameter returns the world.Entity passed as a parameter of a translated death message. This is the
+// name of the entity if it has one, or the translation key of the name of its type otherwise.
+func entityParameter(e world.Entity) string {
+	switch e := e.(type) {
+	case *Player:
+		return e.Name()
+	case interface{ NameTag() string }:
+		if tag := e.NameTag(); tag != "" {
+			return tag
+		}
+	}
+	return "%entity." + strings.TrimPrefix(e.Type().EncodeEntity(), "minecraft:") + ".name"
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/df-mc/dragonfly/server/event"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/player/skin"
+	"github.com/df-mc/dragonfly/server/session"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
 	"net"
@@ -25,12 +26,24 @@ type Handler interface {
 	HandleTeleport(ctx *event.Context, pos mgl64.Vec3)
 	// HandleChangeWorld handles when the player is added to a new world. before may be nil.
 	HandleChangeWorld(before, after *world.World)
+	// HandleRegionEnter handles the player moving into a world.Region, added to its world using
+	// World.AddRegion, with the name passed.
+	HandleRegionEnter(name string, r world.Region)
+	// HandleRegionLeave handles the player moving out of a world.Region, added to its world using
+	// World.AddRegion, with the name passed.
+	HandleRegionLeave(name string, r world.Region)
 	// HandleToggleSprint handles when the player starts or stops sprinting.
 	// After is true if the player is sprinting after toggling (changing their sprinting state).
 	HandleToggleSprint(ctx *event.Context, after bool)
 	// HandleToggleSneak handles when the player starts or stops sneaking.
 	// After is true if the player is sneaking after toggling (changing their sneaking state).
 	HandleToggleSneak(ctx *event.Context, after bool)
+	// HandleToggleFlight handles when the player starts or stops flying. After is true if the player is flying
+	// after toggling. ctx.Cancel() may be called to keep the player in its previous flight state.
+	HandleToggleFlight(ctx *event.Context, after bool)
+	// HandleToggleGlide handles when the player starts or stops gliding with an elytra. After is true if the
+	// player is gliding after toggling. ctx.Cancel() may be called to keep the player in its previous state.
+	HandleToggleGlide(ctx *event.Context, after bool)
 	// HandleChat handles a message sent in the chat by a player. ctx.Cancel() may be called to cancel the
 	// message being sent in chat.
 	// The message may be changed by assigning to *message.
@@ -46,8 +59,14 @@ type Handler interface {
 	// damage being dealt to the player.
 	// The damage dealt to the player may be changed by assigning to *damage.
 	HandleHurt(ctx *event.Context, damage *float64, attackImmunity *time.Duration, src world.DamageSource)
-	// HandleDeath handles the player dying to a particular damage cause.
-	HandleDeath(src world.DamageSource, keepInv *bool)
+	// HandleDeath handles the player dying to a particular damage cause. The death message broadcast to the
+	// players in the world of the player may be changed by assigning to *msg. Assigning an empty string
+	// prevents a death message from being broadcast. *msg holds the English version of the default message,
+	// which is broadcast as a translation, so that players see it in their own language, unless changed.
+	// If *keepInv is false, the items in *drops are removed from the inventories of the player and dropped at
+	// the location of death. Items may be removed from *drops to keep them in the slot they are in, for
+	// example to keep armour, and items may be added to *drops to drop them in addition.
+	HandleDeath(src world.DamageSource, keepInv *bool, drops *[]item.Stack, msg *string)
 	// HandleRespawn handles the respawning of the player in the world. The spawn position passed may be
 	// changed by assigning to *pos. The world.World in which the Player is respawned may be modifying by assigning to
 	// *w. This world may be the world the Player died in, but it might also point to a different world (the overworld)
@@ -56,6 +75,11 @@ type Handler interface {
 	// HandleSkinChange handles the player changing their skin. ctx.Cancel() may be called to cancel the skin
 	// change.
 	HandleSkinChange(ctx *event.Context, skin *skin.Skin)
+	// HandleChunkRadiusChange handles the client of the player requesting a different chunk radius, which
+	// happens when the player changes its render distance. The radius may be changed to send the player a
+	// different number of chunks, but it is always limited to the maximum chunk radius of the server.
+	// ctx.Cancel() may be called to keep the current chunk radius.
+	HandleChunkRadiusChange(ctx *event.Context, radius *int)
 	// HandleStartBreak handles the player starting to break a block at the position passed. ctx.Cancel() may
 	// be called to stop the player from breaking the block completely.
 	HandleStartBreak(ctx *event.Context, pos cube.Pos)
@@ -77,13 +101,20 @@ type Handler interface {
 	// position passed. The face of the block clicked is also passed, along with the relative click position.
 	// The click position has X, Y and Z values which are all in the range 0.0-1.0. It is also called if the
 	// player is holding no item.
+	// ctx.Cancel() may be called to cancel the default behaviour, such as the activation of the block clicked
+	// or the placement of the block held.
 	HandleItemUseOnBlock(ctx *event.Context, pos cube.Pos, face cube.Face, clickPos mgl64.Vec3)
 	// HandleItemUseOnEntity handles the player using the item held in its main hand on an entity passed to
-	// the method.
+	// the method, which happens when the player right-clicks (interacts with) the entity.
 	// HandleItemUseOnEntity is always called when a player uses an item on an entity, regardless of whether
 	// the item actually does anything when used on an entity. It is also called if the player is holding no
-	// item.
+	// item. ctx.Cancel() may be called to prevent the item from being used on the entity.
 	HandleItemUseOnEntity(ctx *event.Context, e world.Entity)
+	// HandleInteract handles another player interacting with (right-clicking) the player. It is called after
+	// the Handler of the other player has handled HandleItemUseOnEntity. HandleInteract may be used to create
+	// NPCs from players created using New, for example for shops. ctx.Cancel() may be called to prevent the
+	// item held by the other player from being used on the player.
+	HandleInteract(ctx *event.Context, by *Player)
 	// HandleItemConsume handles the player consuming an item. This is called whenever a consumable such as
 	// food is consumed.
 	HandleItemConsume(ctx *event.Context, item item.Stack)
@@ -114,10 +145,12 @@ type Handler interface {
 	// the item is passed.
 	HandleItemDamage(ctx *event.Context, i item.Stack, damage int)
 	// HandleItemPickup handles the player picking up an item from the ground. The item stack laying on the
-	// ground is passed. ctx.Cancel() may be called to prevent the player from picking up the item.
+	// ground is passed. ctx.Cancel() may be called to prevent the player from picking up the item, in which
+	// case the item entity remains in the world.
 	HandleItemPickup(ctx *event.Context, i item.Stack)
 	// HandleItemDrop handles the player dropping an item on the ground. The dropped item entity is passed.
-	// ctx.Cancel() may be called to prevent the player from dropping the entity.Item passed on the ground.
+	// ctx.Cancel() may be called to prevent the player from dropping the entity.Item passed on the ground. If
+	// the item was dropped from the inventory of the player, it is kept in the inventory when cancelled.
 	// e.Item() may be called to obtain the item stack dropped.
 	HandleItemDrop(ctx *event.Context, e *entity.Item)
 	// HandleTransfer handles a player being transferred to another server. ctx.Cancel() may be called to
@@ -126,9 +159,18 @@ type Handler interface {
 	// HandleCommandExecution handles the command execution of a player, who wrote a command in the chat.
 	// ctx.Cancel() may be called to cancel the command execution.
 	HandleCommandExecution(ctx *event.Context, command cmd.Command, args []string)
+	// HandleIdle handles the player being idle for longer than the idle timeout set using
+	// Player.SetIdleTimeout. ctx.Cancel() may be called to prevent the player from being kicked, for example
+	// to teleport the player to an AFK area instead. The idle timer of the player is reset either way.
+	HandleIdle(ctx *event.Context)
+	// HandleFlag handles the player being flagged by one of the movement checks of the player, such as
+	// SpeedCheck. violations is the total amount of times the player was flagged by the check, which may be
+	// used to decide to kick the player. ctx.Cancel() may be called to allow the action that was flagged.
+	HandleFlag(ctx *event.Context, check string, violations int)
 	// HandleQuit handles the closing of a player. It is always called when the player is disconnected,
-	// regardless of the reason.
-	HandleQuit()
+	// regardless of the reason. The reason passed specifies how the connection of the player ended, such as
+	// the player quitting, timing out or being kicked.
+	HandleQuit(reason session.DisconnectReason)
 }
 
 // NopHandler implements the Handler interface but does not execute any code when an event is called. The
@@ -144,12 +186,17 @@ func (NopHandler) HandleMove(*event.Context, mgl64.Vec3, float64, float64)
 func (NopHandler) HandleJump()                                                                {}
 func (NopHandler) HandleTeleport(*event.Context, mgl64.Vec3)                                  {}
 func (NopHandler) HandleChangeWorld(*world.World, *world.World)                               {}
+func (NopHandler) HandleRegionEnter(string, world.Region)                                     {}
+func (NopHandler) HandleRegionLeave(string, world.Region)                                     {}
 func (NopHandler) HandleToggleSprint(*event.Context, bool)                                    {}
 func (NopHandler) HandleToggleSneak(*event.Context, bool)                                     {}
+func (NopHandler) HandleToggleFlight(*event.Context, bool)                                    {}
+func (NopHandler) HandleToggleGlide(*event.Context, bool)                                     {}
 func (NopHandler) HandleCommandExecution(*event.Context, cmd.Command, []string)               {}
 func (NopHandler) HandleTransfer(*event.Context, *net.UDPAddr)                                {}
 func (NopHandler) HandleChat(*event.Context, *string)                                         {}
 func (NopHandler) HandleSkinChange(*event.Context, *skin.Skin)                                {}
+func (NopHandler) HandleChunkRadiusChange(*event.Context, *int)                               {}
 func (NopHandler) HandleStartBreak(*event.Context, cube.Pos)                                  {}
 func (NopHandler) HandleBlockBreak(*event.Context, cube.Pos, *[]item.Stack, *int)             {}
 func (NopHandler) HandleBlockPlace(*event.Context, cube.Pos, world.Block)                     {}
@@ -159,6 +206,7 @@ func (NopHandler) HandleItemPickup(*event.Context, item.Stack)
 func (NopHandler) HandleItemUse(*event.Context)                                               {}
 func (NopHandler) HandleItemUseOnBlock(*event.Context, cube.Pos, cube.Face, mgl64.Vec3)       {}
 func (NopHandler) HandleItemUseOnEntity(*event.Context, world.Entity)                         {}
+func (NopHandler) HandleInteract(*event.Context, *Player)                                     {}
 func (NopHandler) HandleItemConsume(*event.Context, item.Stack)                               {}
 func (NopHandler) HandleItemDamage(*event.Context, item.Stack, int)                           {}
 func (NopHandler) HandleAttackEntity(*event.Context, world.Entity, *float64, *float64, *bool) {}
@@ -167,6 +215,8 @@ func (NopHandler) HandlePunchAir(*event.Context)
 func (NopHandler) HandleHurt(*event.Context, *float64, *time.Duration, world.DamageSource)    {}
 func (NopHandler) HandleHeal(*event.Context, *float64, world.HealingSource)                   {}
 func (NopHandler) HandleFoodLoss(*event.Context, int, *int)                                   {}
-func (NopHandler) HandleDeath(world.DamageSource, *bool)                                      {}
+func (NopHandler) HandleDeath(world.DamageSource, *bool, *[]item.Stack, *string)              {}
 func (NopHandler) HandleRespawn(*mgl64.Vec3, **world.World)                                   {}
-func (NopHandler) HandleQuit()                                                                {}
+func (NopHandler) HandleIdle(*event.Context)                                                  {}
+func (NopHandler) HandleFlag(*event.Context, string, int)                                     {}
+func (NopHandler) HandleQuit(session.DisconnectReason)                                        {}
@@ -12,6 +12,7 @@ import (
 	"github.com/df-mc/atomic"
 	"github.com/df-mc/dragonfly/server/block"
 	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/block/cube/trace"
 	"github.com/df-mc/dragonfly/server/block/model"
 	"github.com/df-mc/dragonfly/server/cmd"
 	"github.com/df-mc/dragonfly/server/entity"
@@ -33,7 +34,11 @@ import (
 	"github.com/df-mc/dragonfly/server/world/sound"
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/sandertv/gophertunnel/minecraft/text"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	"golang.org/x/text/language"
 )
 
@@ -64,8 +69,14 @@ type Player struct {
 	heldSlot                 *atomic.Uint32
 
 	sneaking, sprinting, swimming, gliding, flying,
-	invisible, immobile, onGround, usingItem atomic.Bool
+	invisible, immobile, onGround, usingItem, operator atomic.Bool
 	usingSince atomic.Int64
+	// allowFlight is true if the player may fly regardless of its game mode.
+	allowFlight atomic.Bool
+
+	metadataMu sync.Mutex
+	metadata   map[uint32]any
+	flags      map[uint8]bool
 
 	glideTicks   atomic.Int64
 	fireTicks    atomic.Int64
@@ -79,14 +90,41 @@ type Player struct {
 	cooldowns  map[string]time.Time
 	// lastTickedWorld holds the world that the player was in, in the last tick.
 	lastTickedWorld *world.World
+	// lastTick is the current tick of the world that the player was in, in the last tick.
+	lastTick atomic.Int64
+	// stateMu is held while Move and Tick update the movement state of the player, so that a Snapshot never
+	// holds part of the state before and part of it after such an update.
+	stateMu sync.RWMutex
 
 	speed      atomic.Float64
+	flySpeed   atomic.Float64
 	health     *entity.HealthManager
 	experience *entity.ExperienceManager
 	effects    *entity.EffectManager
 
 	lastXPPickup atomic.Value[time.Time]
+	lastTold     atomic.Value[*Player]
+	lastActive   atomic.Value[time.Time]
+	idleTimeout  atomic.Value[time.Duration]
+	chatFormat   atomic.Value[string]
+	idleWarned   atomic.Bool
+	idleMessage  atomic.Value[string]
+	provider     atomic.Value[Provider]
+	reach        atomic.Float64
 	immunity     atomic.Value[time.Time]
+	// lastDamage holds the last damage dealt to the player and lastAttack the last damage dealt by another
+	// entity. They are used for attributing deaths and tagging players in combat.
+	lastDamage, lastAttack atomic.Value[damageRecord]
+
+	statsMu sync.Mutex
+	stats   Statistics
+
+	// maxMoveSpeed is the maximum horizontal distance the player may move per tick, as set using
+	// SetMaxMoveSpeed. velocityAt is the time at which the velocity of the player was last changed.
+	maxMoveSpeed atomic.Float64
+	velocityAt   atomic.Value[time.Time]
+	violationsMu sync.Mutex
+	violations   map[string]int
 
 	deathMu        sync.Mutex
 	deathPos       *mgl64.Vec3
@@ -94,6 +132,11 @@ type Player struct {
 
 	enchantSeed atomic.Int64
 
+	// joinTime is the time at which the Player was created. playtime is the total playtime of the Player in
+	// previous sessions, as loaded from its Data.
+	joinTime time.Time
+	playtime time.Duration
+
 	mc *entity.MovementComputer
 
 	collidedVertically, collidedHorizontally atomic.Bool
@@ -101,6 +144,10 @@ type Player struct {
 	breaking          atomic.Bool
 	breakingPos       atomic.Value[cube.Pos]
 	lastBreakDuration time.Duration
+	// breakProgress is the fraction of the block currently being broken that
+	// has been broken, as of breakUpdated.
+	breakProgress float64
+	breakUpdated  time.Time
 
 	breakParticleCounter atomic.Uint32
 
@@ -110,6 +157,9 @@ type Player struct {
 // New returns a new initialised player. A random UUID is generated for the player, so that it may be
 // identified over network. You can either pass on player data you want to load or
 // you can leave the data as nil to use default data.
+// Players created using New are not controlled by a client and may be used as NPCs: They are shown with the
+// skin passed once added to a world using world.World.AddEntity, and interactions of other players with them
+// are passed to Handler.HandleInteract.
 func New(name string, skin skin.Skin, pos mgl64.Vec3) *Player {
 	p := &Player{}
 	*p = Player{
@@ -131,6 +181,7 @@ func New(name string, skin skin.Skin, pos mgl64.Vec3) *Player {
 		name:              name,
 		skin:              *atomic.NewValue(skin),
 		speed:             *atomic.NewFloat64(0.1),
+		flySpeed:          *atomic.NewFloat64(0.05),
 		nameTag:           *atomic.NewValue(name),
 		heldSlot:          atomic.NewUint32(0),
 		locale:            language.BritishEnglish,
@@ -139,7 +190,12 @@ func New(name string, skin skin.Skin, pos mgl64.Vec3) *Player {
 		maxAirSupplyTicks: *atomic.NewInt64(300),
 		enchantSeed:       *atomic.NewInt64(rand.Int63()),
 		scale:             *atomic.NewFloat64(1),
+		reach:             *atomic.NewFloat64(8),
+		chatFormat:        *atomic.NewValue("<%v> %v"),
+		idleMessage:       *atomic.NewValue("You have been kicked for being idle."),
 		immunity:          *atomic.NewValue(time.Now()),
+		lastActive:        *atomic.NewValue(time.Now()),
+		joinTime:          time.Now(),
 		pos:               *atomic.NewValue(pos),
 		cooldowns:         make(map[string]time.Time),
 		mc:                &entity.MovementComputer{Gravity: 0.08, Drag: 0.02, DragBeforeGravity: true},
@@ -192,6 +248,106 @@ func (p *Player) XUID() string {
 	return p.xuid
 }
 
+// SetOperator sets if the Player is an operator. Operators are shown the operator permission level in-game.
+// Commands may check if a Source is an operator by asserting it to an interface{ Operator() bool } in their
+// Allow method, to limit administrative commands to operators.
+func (p *Player) SetOperator(op bool) {
+	if p.operator.Swap(op) == op {
+		return
+	}
+	p.session().SendAbilities()
+}
+
+// JoinTime returns the time at which the Player joined the server.
+func (p *Player) JoinTime() time.Time {
+	return p.joinTime
+}
+
+// SessionPlaytime returns the duration that has passed since the Player joined the server.
+func (p *Player) SessionPlaytime() time.Duration {
+	return time.Since(p.joinTime)
+}
+
+// Playtime returns the total duration that the Player has played on the server, including previous sessions
+// loaded from its Data. Playtime is saved as part of the Data of the Player, so that it persists across
+// restarts if a Provider is used.
+func (p *Player) Playtime() time.Duration {
+	return p.playtime + p.SessionPlaytime()
+}
+
+// SetReach sets the maximum distance from the eyes of the Player to blocks and entities that it may interact with
+// when not in creative mode. Interactions beyond this distance, for example by clients with reach hacks, are
+// rejected. A small margin is added on top of the reach depending on the latency of the Player to prevent false
+// positives. The default reach is 8 blocks.
+func (p *Player) SetReach(reach float64) {
+	p.reach.Store(reach)
+}
+
+// Reach returns the maximum distance from the eyes of the Player to blocks and entities that it may interact
+// with when not in creative mode, as set using SetReach.
+func (p *Player) Reach() float64 {
+	return p.reach.Load()
+}
+
+// SetIdleTimeout sets the duration after which the Player is kicked if it has not moved or sent any input. The
+// Player is warned shortly before being kicked, and Handler.HandleIdle is called before kicking it, so that the
+// kick may be cancelled. Passing 0 disables kicking idle players, which is the default.
+func (p *Player) SetIdleTimeout(d time.Duration) {
+	p.idleTimeout.Store(d)
+	p.resetIdle()
+}
+
+// SetIdleMessage sets the message shown to the Player when it is kicked for being idle. It may also be a
+// translation key, which is translated by the client. The default message is 'You have been kicked for being
+// idle.'.
+func (p *Player) SetIdleMessage(msg string) {
+	p.idleMessage.Store(msg)
+}
+
+// IdleDuration returns the duration that has passed since the Player last moved or sent any input.
+func (p *Player) IdleDuration() time.Duration {
+	return time.Since(p.lastActive.Load())
+}
+
+// resetIdle resets the idle timer of the Player.
+func (p *Player) resetIdle() {
+	p.lastActive.Store(time.Now())
+	p.idleWarned.Store(false)
+}
+
+// tickIdle warns the Player if it is about to be kicked for being idle and kicks it once it has been idle for
+// longer than its idle timeout.
+func (p *Player) tickIdle() {
+	timeout := p.idleTimeout.Load()
+	if timeout <= 0 || p.session() == session.Nop {
+		return
+	}
+	idle := p.IdleDuration()
+	if idle >= timeout {
+		ctx := event.C()
+		p.Handler().HandleIdle(ctx)
+		p.resetIdle()
+		if !ctx.Cancelled() {
+			p.Disconnect(p.idleMessage.Load())
+		}
+		return
+	}
+	// Warn the player 30 seconds before being kicked, or halfway through the timeout if it is shorter than a
+	// minute.
+	warnAt := timeout - time.Second*30
+	if warnAt < timeout/2 {
+		warnAt = timeout / 2
+	}
+	if idle >= warnAt && !p.idleWarned.Swap(true) {
+		p.Message(text.Colourf("<yellow>You will be kicked for being idle in %v.</yellow>", (timeout - idle).Round(time.Second)))
+	}
+}
+
+// Operator checks if the Player is an operator, as set using SetOperator.
+func (p *Player) Operator() bool {
+	return p.operator.Load()
+}
+
 // DeviceID returns the device ID of the player. If the Player is not connected to a network session, an empty string is
 // returned. Otherwise, the device ID the network session sent in the ClientData is returned.
 func (p *Player) DeviceID() string {
@@ -210,6 +366,25 @@ func (p *Player) DeviceModel() string {
 	return p.session().ClientData().DeviceModel
 }
 
+// DeviceOS returns the operating system of the device of the player. If the Player is not connected to a network
+// session, 0 is returned. Otherwise, the device OS the network session sent in the ClientData is returned.
+func (p *Player) DeviceOS() protocol.DeviceOS {
+	if p.session() == session.Nop {
+		return 0
+	}
+	return p.session().ClientData().DeviceOS
+}
+
+// GameVersion returns the version of the game that the player is playing on, such as '1.19.63'. If the Player is
+// not connected to a network session, an empty string is returned. Otherwise, the game version the network session
+// sent in the ClientData is returned.
+func (p *Player) GameVersion() string {
+	if p.session() == session.Nop {
+		return ""
+	}
+	return p.session().ClientData().GameVersion
+}
+
 // SelfSignedID returns the self-signed ID of the player. If the Player is not connected to a network session, an empty
 // string is returned. Otherwise, the self-signed ID the network session sent in the ClientData is returned.
 func (p *Player) SelfSignedID() string {
@@ -251,6 +426,49 @@ func (p *Player) SetSkin(skin skin.Skin) {
 	}
 }
 
+// ChunkRadius returns the chunk radius of the Player: The radius in chunks around the Player in which chunks
+// are sent to its client. If the Player does not have a session associated with it, ChunkRadius returns 0.
+func (p *Player) ChunkRadius() int {
+	if p.session() == session.Nop {
+		return 0
+	}
+	return p.session().ChunkRadius()
+}
+
+// SetChunkRadius changes the chunk radius of the Player, limited to the maximum chunk radius of the server.
+// The client of the Player may request a different radius afterwards when the player changes its render
+// distance, which calls Handler.HandleChunkRadiusChange.
+func (p *Player) SetChunkRadius(radius int) {
+	p.session().SetChunkRadius(radius)
+}
+
+// ChunksPerTick returns the maximum amount of chunks sent to the Player every tick. Chunks are sent in a
+// spiral outwards from the chunk the Player is in. If the Player does not have a session associated with it,
+// ChunksPerTick returns 0.
+func (p *Player) ChunksPerTick() int {
+	if p.session() == session.Nop {
+		return 0
+	}
+	return p.session().ChunksPerTick()
+}
+
+// SetChunksPerTick sets the maximum amount of chunks sent to the Player every tick. Values lower than 1 are
+// changed to 1. Note that the server changes this value for all players when it starts or stops lagging
+// behind, if the LowTPSChunksPerTick of its Config is set.
+func (p *Player) SetChunksPerTick(n int) {
+	p.session().SetChunksPerTick(n)
+}
+
+// RequestChunkRadius handles a request of the client of the Player to change its chunk radius to the radius
+// passed. Handler.HandleChunkRadiusChange is called before the radius is changed.
+func (p *Player) RequestChunkRadius(radius int) {
+	ctx := event.C()
+	if p.Handler().HandleChunkRadiusChange(ctx, &radius); ctx.Cancelled() {
+		radius = p.ChunkRadius()
+	}
+	p.SetChunkRadius(radius)
+}
+
 // Locale returns the language and locale of the Player, as selected in the Player's settings.
 func (p *Player) Locale() language.Tag {
 	return p.locale
@@ -278,6 +496,22 @@ func (p *Player) Messagef(f string, a ...any) {
 	p.session().SendMessage(fmt.Sprintf(f, a...))
 }
 
+// Tell sends a private message from another Player to the player. The message is shown in grey and italic,
+// prefixed with the name of the sender, like messages sent using the vanilla /tell command. The sender is
+// recorded so that it can be retrieved using LastTold, for example to implement a /reply command.
+func (p *Player) Tell(from *Player, msg string) {
+	p.lastTold.Store(from)
+	p.Message(text.Colourf("<grey><i>%v whispers to you: %v</i></grey>", from.Name(), msg))
+}
+
+// LastTold returns the Player that most recently sent a private message to the player using Tell. If no
+// private message was received yet, false is returned. Note that the Player returned may have left the server
+// since sending the message.
+func (p *Player) LastTold() (*Player, bool) {
+	from := p.lastTold.Load()
+	return from, from != nil
+}
+
 // SendPopup sends a formatted popup to the player. The popup is shown above the hotbar of the player and
 // overwrites/is overwritten by the name of the item equipped.
 // The popup is formatted following the rules of fmt.Sprintln without a newline at the end.
@@ -337,6 +571,30 @@ func (p *Player) SendScoreboard(scoreboard *scoreboard.Scoreboard) {
 	p.session().SendScoreboard(scoreboard)
 }
 
+// SendScoreboardEntries sends a scoreboard with the title and entries passed to the player, so that the score
+// shown next to every line may be chosen. At most 15 entries are shown. The lines are sorted by their score in
+// ascending order and are not padded.
+func (p *Player) SendScoreboardEntries(title string, entries []scoreboard.Entry) {
+	sb := scoreboard.New(title)
+	sb.RemovePadding()
+	for i, e := range entries {
+		if i >= 15 {
+			break
+		}
+		sb.Set(i, e.Text)
+		sb.SetScore(i, e.Score)
+	}
+	p.session().SendScoreboard(sb)
+}
+
+// UpdateScoreboardEntry changes the text and score of a single line of the scoreboard currently shown to the
+// player, without sending the whole scoreboard again. This is useful for lines that change often, such as
+// timers. The text is not padded. Nothing happens if no scoreboard is shown or if the index is not between 0
+// and 14.
+func (p *Player) UpdateScoreboardEntry(index int, e scoreboard.Entry) {
+	p.session().UpdateScoreboardEntry(index, e)
+}
+
 // RemoveScoreboard removes any scoreboard currently present on the screen of the player. Nothing happens if
 // the player has no scoreboard currently active.
 func (p *Player) RemoveScoreboard() {
@@ -359,12 +617,27 @@ func (p *Player) RemoveBossBar() {
 // Chat writes a message in the global chat (chat.Global). The message is prefixed with the name of the
 // player and is formatted following the rules of fmt.Sprintln.
 func (p *Player) Chat(msg ...any) {
+	p.resetIdle()
 	message := format(msg)
 	ctx := event.C()
 	if p.Handler().HandleChat(ctx, &message); ctx.Cancelled() {
 		return
 	}
-	_, _ = fmt.Fprintf(chat.Global, "<%v> %v\n", p.name, message)
+	_, _ = fmt.Fprintf(chat.Global, p.ChatFormat()+"\n", p.name, message)
+}
+
+// SetChatFormat sets the format of chat messages sent by the player. The format must have two '%v' arguments,
+// which are replaced with the name of the player and the message sent, in that order. Minecraft colour codes
+// may be used, for example to show a rank in front of the name of the player. If called from Handler.HandleChat,
+// the format also applies to the message being handled. The format remains in use for all later messages, so
+// it must be set back to change the format of a single message. The default format is '<%v> %v'.
+func (p *Player) SetChatFormat(format string) {
+	p.chatFormat.Store(format)
+}
+
+// ChatFormat returns the format of chat messages sent by the player, as set using SetChatFormat.
+func (p *Player) ChatFormat() string {
+	return p.chatFormat.Load()
 }
 
 // ExecuteCommand executes a command passed as the player. If the command could not be found, or if the usage
@@ -374,6 +647,7 @@ func (p *Player) ExecuteCommand(commandLine string) {
 	if p.Dead() {
 		return
 	}
+	p.resetIdle()
 	args := strings.Split(commandLine, " ")
 	command, ok := cmd.ByAlias(args[0][1:])
 	if !ok {
@@ -439,6 +713,19 @@ func (p *Player) DisableInstantRespawn() {
 	p.session().EnableInstantRespawn(false)
 }
 
+// SendFog sends a stack of fog identifiers, such as "minecraft:fog_ocean" and "minecraft:fog_hell", to the
+// player, replacing the fog previously sent. Fogs later in the stack are rendered over those before it. Custom
+// fogs may be added using resource packs.
+func (p *Player) SendFog(stack []string) {
+	p.session().SendFog(stack)
+}
+
+// ClearFog removes all fog previously sent using SendFog, so that the player sees the default fog of the
+// biome it is in again.
+func (p *Player) ClearFog() {
+	p.session().SendFog(nil)
+}
+
 // SetNameTag changes the name tag displayed over the player in-game. Changing the name tag does not change
 // the player's name in, for example, the player list or the chat.
 func (p *Player) SetNameTag(name string) {
@@ -464,8 +751,9 @@ func (p *Player) ScoreTag() string {
 }
 
 // SetSpeed sets the speed of the player. The value passed is the blocks/tick speed that the player will then
-// obtain.
+// obtain. Negative values are treated as 0. The default speed of a player is 0.1.
 func (p *Player) SetSpeed(speed float64) {
+	speed = math.Max(speed, 0)
 	p.speed.Store(speed)
 	p.session().SendSpeed(speed)
 }
@@ -476,6 +764,19 @@ func (p *Player) Speed() float64 {
 	return p.speed.Load()
 }
 
+// SetFlySpeed sets the speed at which the player flies, if its game mode allows flying. The value passed is
+// clamped between 0 and 1, as the client does not handle higher speeds well. The default fly speed of a
+// player is 0.05.
+func (p *Player) SetFlySpeed(flySpeed float64) {
+	p.flySpeed.Store(math.Min(math.Max(flySpeed, 0), 1))
+	p.session().SendAbilities()
+}
+
+// FlySpeed returns the speed at which the player flies. The default fly speed of a player is 0.05.
+func (p *Player) FlySpeed() float64 {
+	return p.flySpeed.Load()
+}
+
 // Health returns the current health of the player. It will always be lower than Player.MaxHealth().
 func (p *Player) Health() float64 {
 	return p.health.Health()
@@ -553,6 +854,9 @@ func (p *Player) fall(distance float64) {
 	if dmg < 0.5 {
 		return
 	}
+	if fallDamage, _ := w.Gamerule("fallDamage").(bool); !fallDamage {
+		return
+	}
 	p.Hurt(math.Ceil(dmg), entity.FallDamageSource{})
 }
 
@@ -568,6 +872,9 @@ func (p *Player) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
 	if _, ok := p.Effect(effect.FireResistance{}); (ok && src.Fire()) || p.Dead() || !p.GameMode().AllowsTakingDamage() {
 		return 0, false
 	}
+	if attackedByPlayer(src) && !p.World().PvP() {
+		return 0, false
+	}
 	immunity := time.Second / 2
 	ctx := event.C()
 	if p.Handler().HandleHurt(ctx, &dmg, &immunity, src); ctx.Cancelled() {
@@ -590,6 +897,7 @@ func (p *Player) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
 		}
 	}
 	p.addHealth(-damageLeft)
+	p.recordDamage(src)
 
 	if src.ReducedByArmour() {
 		p.Exhaust(0.1)
@@ -621,12 +929,7 @@ func (p *Player) Hurt(dmg float64, src world.DamageSource) (float64, bool) {
 // applyThorns applies thorns damage to the attacking entity if the world.DamageSource is either damage.AttackDamageSource or
 // damage.ProjectileDamageSource.
 func (p *Player) applyThorns(src world.DamageSource) {
-	var attacker world.Entity
-	if s, ok := src.(entity.AttackDamageSource); ok {
-		attacker = s.Attacker
-	} else if s, ok := src.(entity.ProjectileDamageSource); ok {
-		attacker = s.Owner
-	}
+	attacker := damageAttacker(src)
 	l, ok := attacker.(entity.Living)
 	if !ok {
 		// Not attacked by a living entity.
@@ -883,14 +1186,30 @@ func (p *Player) kill(src world.DamageSource) {
 
 	p.addHealth(-p.MaxHealth())
 
-	keepInv := false
-	p.Handler().HandleDeath(src, &keepInv)
+	keepInv, _ := p.World().Gamerule("keepInventory").(bool)
+	def, key, params := deathMessage(p.Name(), src)
+	msg := def
+	p.session().EmptyUIInventory()
+	drops := p.deathDrops()
+	p.Handler().HandleDeath(src, &keepInv, &drops, &msg)
 	p.StopSneaking()
 	p.StopSprinting()
 
 	w, pos := p.World(), p.Position()
+	if show, _ := w.Gamerule("showDeathMessages").(bool); show && msg != "" {
+		for _, e := range w.Entities() {
+			if viewer, ok := e.(*Player); ok {
+				if msg == def {
+					// The message was not changed, so it can be translated to the language of the viewer.
+					viewer.session().SendTranslation(key, params...)
+					continue
+				}
+				viewer.Message(msg)
+			}
+		}
+	}
 	if !keepInv {
-		p.dropContents()
+		p.dropContents(drops)
 	}
 	for _, e := range p.Effects() {
 		p.RemoveEffect(e.Type())
@@ -916,8 +1235,22 @@ func (p *Player) kill(src world.DamageSource) {
 	})
 }
 
-// dropContents drops all items and experience of the Player on the ground in random directions.
-func (p *Player) dropContents() {
+// deathDrops returns the items in the inventories of the Player that are dropped when it dies. Items with the
+// curse of vanishing are not included.
+func (p *Player) deathDrops() []item.Stack {
+	var drops []item.Stack
+	for _, it := range append(p.inv.Items(), append(p.armour.Items(), p.offHand.Items()...)...) {
+		if _, ok := it.Enchantment(enchantment.CurseOfVanishing{}); !ok {
+			drops = append(drops, it)
+		}
+	}
+	return drops
+}
+
+// dropContents drops the items passed and all experience of the Player on the ground in random directions. The
+// items passed are removed from the inventories of the Player, together with items with the curse of vanishing.
+// Other items are kept.
+func (p *Player) dropContents(drops []item.Stack) {
 	w, pos := p.World(), p.Position()
 	for _, orb := range entity.NewExperienceOrbs(pos, int(math.Min(float64(p.experience.Level()*7), 100))) {
 		orb.SetVelocity(mgl64.Vec3{(rand.Float64()*0.2 - 0.1) * 2, rand.Float64() * 0.4, (rand.Float64()*0.2 - 0.1) * 2})
@@ -926,11 +1259,23 @@ func (p *Player) dropContents() {
 	p.experience.Reset()
 	p.session().SendExperience(p.experience)
 
-	p.session().EmptyUIInventory()
-	for _, it := range append(p.inv.Clear(), append(p.armour.Clear(), p.offHand.Clear()...)...) {
-		if _, ok := it.Enchantment(enchantment.CurseOfVanishing{}); ok {
-			continue
+	remaining := slices.Clone(drops)
+	for _, inv := range []*inventory.Inventory{p.inv, p.armour.Inventory(), p.offHand} {
+		for slot, it := range inv.Slots() {
+			if it.Empty() {
+				continue
+			}
+			if _, ok := it.Enchantment(enchantment.CurseOfVanishing{}); ok {
+				_ = inv.SetItem(slot, item.Stack{})
+				continue
+			}
+			if i := slices.IndexFunc(remaining, it.Equal); i != -1 {
+				remaining = slices.Delete(remaining, i, i+1)
+				_ = inv.SetItem(slot, item.Stack{})
+			}
 		}
+	}
+	for _, it := range drops {
 		ent := entity.NewItem(it, pos)
 		ent.SetVelocity(mgl64.Vec3{rand.Float64()*0.2 - 0.1, 0.2, rand.Float64()*0.2 - 0.1})
 		w.AddEntity(ent)
@@ -950,12 +1295,12 @@ func (p *Player) Respawn() {
 	p.Extinguish()
 	p.ResetFallDistance()
 
-	// We can use the principle here that returning through a portal of a specific dimension inside that dimension will
-	// always bring us back to the overworld.
-	w = w.PortalDestination(w.Dimension())
+	w = p.respawnWorld()
 	pos := w.PlayerSpawn(p.UUID()).Vec3Middle()
 
 	p.Handler().HandleRespawn(&pos, &w)
+	p.lastDamage.Store(damageRecord{})
+	p.lastAttack.Store(damageRecord{})
 
 	w.AddEntity(p)
 	p.Teleport(pos)
@@ -964,6 +1309,35 @@ func (p *Player) Respawn() {
 	p.SetVisible()
 }
 
+// SetSpawn sets the position that the Player respawns at after dying, overriding the spawn of the world. The
+// spawn is set in the world that the Player respawns in, which is the overworld if the Player is in the nether
+// or the end.
+func (p *Player) SetSpawn(pos cube.Pos) {
+	w := p.respawnWorld()
+	if w == nil {
+		return
+	}
+	w.SetPlayerSpawn(p.UUID(), pos)
+	p.session().SendSpawnPosition(pos, w.Dimension())
+}
+
+// Spawn returns the position that the Player respawns at after dying. If no spawn was set using SetSpawn, the
+// spawn of the world that the Player respawns in is returned.
+func (p *Player) Spawn() cube.Pos {
+	return p.respawnWorld().PlayerSpawn(p.UUID())
+}
+
+// respawnWorld returns the world that the Player respawns in after dying.
+func (p *Player) respawnWorld() *world.World {
+	// We can use the principle here that returning through a portal of a specific dimension inside that dimension will
+	// always bring us back to the overworld.
+	w := p.World()
+	if w == nil {
+		return nil
+	}
+	return w.PortalDestination(w.Dimension())
+}
+
 // StartSprinting makes a player start sprinting, increasing the speed of the player by 30% and making
 // particles show up under the feet. The player will only start sprinting if its food level is high enough.
 // If the player is sneaking when calling StartSprinting, it is stopped from sneaking.
@@ -1063,6 +1437,15 @@ func (p *Player) StopSwimming() {
 
 // StartGliding makes the player start gliding if it is not currently doing so.
 func (p *Player) StartGliding() {
+	if p.Gliding() {
+		return
+	}
+	ctx := event.C()
+	if p.Handler().HandleToggleGlide(ctx, true); ctx.Cancelled() {
+		// Send the state of the player to make the client stop gliding again.
+		p.updateState()
+		return
+	}
 	if !p.gliding.CAS(false, true) {
 		return
 	}
@@ -1080,6 +1463,19 @@ func (p *Player) Gliding() bool {
 
 // StopGliding makes the player stop gliding if it is currently doing so.
 func (p *Player) StopGliding() {
+	if !p.Gliding() {
+		return
+	}
+	ctx := event.C()
+	if p.Handler().HandleToggleGlide(ctx, false); ctx.Cancelled() {
+		p.updateState()
+		return
+	}
+	p.stopGliding()
+}
+
+// stopGliding makes the player stop gliding without calling the Handler of the player.
+func (p *Player) stopGliding() {
 	if !p.gliding.CAS(true, false) {
 		return
 	}
@@ -1087,10 +1483,36 @@ func (p *Player) StopGliding() {
 	p.updateState()
 }
 
+// SetAllowFlight sets if the player may fly regardless of its game mode, for example to allow staff members to
+// fly in survival mode. If flight is disallowed while the player is flying and its game mode does not allow
+// flying, the player stops flying.
+func (p *Player) SetAllowFlight(allow bool) {
+	p.allowFlight.Store(allow)
+	if !p.CanFly() {
+		p.stopFlying()
+	}
+	p.session().SendAbilities()
+}
+
+// CanFly checks if the player may fly, either because its game mode allows flying or because flight was allowed
+// using SetAllowFlight.
+func (p *Player) CanFly() bool {
+	return p.allowFlight.Load() || p.GameMode().AllowsFlying()
+}
+
 // StartFlying makes the player start flying if they aren't already. It requires the player to be in a gamemode which
-// allows flying.
+// allows flying, or to be allowed to fly using SetAllowFlight.
 func (p *Player) StartFlying() {
-	if !p.GameMode().AllowsFlying() || !p.flying.CAS(false, true) {
+	if !p.CanFly() || p.Flying() {
+		return
+	}
+	ctx := event.C()
+	if p.Handler().HandleToggleFlight(ctx, true); ctx.Cancelled() {
+		// Send the abilities of the player to make the client stop flying again.
+		p.session().SendAbilities()
+		return
+	}
+	if !p.flying.CAS(false, true) {
 		return
 	}
 	p.session().SendGameMode(p.GameMode())
@@ -1103,6 +1525,19 @@ func (p *Player) Flying() bool {
 
 // StopFlying makes the player stop flying if it currently is.
 func (p *Player) StopFlying() {
+	if !p.Flying() {
+		return
+	}
+	ctx := event.C()
+	if p.Handler().HandleToggleFlight(ctx, false); ctx.Cancelled() {
+		p.session().SendAbilities()
+		return
+	}
+	p.stopFlying()
+}
+
+// stopFlying makes the player stop flying without calling the Handler of the player.
+func (p *Player) stopFlying() {
 	if !p.flying.CAS(true, false) {
 		return
 	}
@@ -1118,6 +1553,10 @@ func (p *Player) Jump() {
 
 	p.Handler().HandleJump()
 	if p.OnGround() {
+		p.statsMu.Lock()
+		p.stats.Jumps++
+		p.statsMu.Unlock()
+
 		jumpVel := 0.42
 		if e, ok := p.Effect(effect.JumpBoost{}); ok {
 			jumpVel = float64(e.Level()) / 10
@@ -1180,6 +1619,55 @@ func (p *Player) Immobile() bool {
 	return p.immobile.Load()
 }
 
+// SetMetadata sets a custom entity metadata value for the key passed, which should be one of the
+// protocol.EntityDataKey constants. The value overrides the value that would otherwise be sent for the key and
+// is sent to all viewers of the player immediately. Passing a nil value removes a custom value previously set.
+// SetMetadata should only be used for metadata not otherwise supported, as the value is not validated.
+func (p *Player) SetMetadata(key uint32, value any) {
+	p.metadataMu.Lock()
+	if value == nil {
+		delete(p.metadata, key)
+	} else {
+		if p.metadata == nil {
+			p.metadata = make(map[uint32]any)
+		}
+		p.metadata[key] = value
+	}
+	p.metadataMu.Unlock()
+	p.updateState()
+}
+
+// SetMetadataFlag enables or disables the entity metadata flag with the index passed, which should be one of
+// the protocol.EntityDataFlag constants. The flag overrides the flag set by the server, without affecting any
+// other flags, and is sent to all viewers of the player immediately. ClearMetadataFlag may be used to remove
+// the override again.
+func (p *Player) SetMetadataFlag(index uint8, enabled bool) {
+	p.metadataMu.Lock()
+	if p.flags == nil {
+		p.flags = make(map[uint8]bool)
+	}
+	p.flags[index] = enabled
+	p.metadataMu.Unlock()
+	p.updateState()
+}
+
+// ClearMetadataFlag removes an override of the entity metadata flag with the index passed previously set using
+// SetMetadataFlag, so that the flag is set by the server again.
+func (p *Player) ClearMetadataFlag(index uint8) {
+	p.metadataMu.Lock()
+	delete(p.flags, index)
+	p.metadataMu.Unlock()
+	p.updateState()
+}
+
+// CustomMetadata returns the custom entity metadata values and flags set using SetMetadata and
+// SetMetadataFlag.
+func (p *Player) CustomMetadata() (values map[uint32]any, flags map[uint8]bool) {
+	p.metadataMu.Lock()
+	defer p.metadataMu.Unlock()
+	return maps.Clone(p.metadata), maps.Clone(p.flags)
+}
+
 // FireProof checks if the Player is currently fireproof. True is returned if the player has a FireResistance effect or
 // if it is in creative mode.
 func (p *Player) FireProof() bool {
@@ -1217,6 +1705,8 @@ func (p *Player) Inventory() *inventory.Inventory {
 
 // Armour returns the armour inventory of the player. This inventory yields 4 slots, for the helmet,
 // chestplate, leggings and boots respectively.
+// Changes to the armour worn, both through the inventory and by using an armour item, may be restricted by
+// assigning an inventory.Handler using Armour().Handle and cancelling HandlePlace.
 func (p *Player) Armour() *inventory.Armour {
 	return p.armour
 }
@@ -1246,6 +1736,8 @@ func (p *Player) EnderChestInventory() *inventory.Inventory {
 
 // SetGameMode sets the game mode of a player. The game mode specifies the way that the player can interact
 // with the world that it is in.
+// Switching to a game mode that is not visible, such as world.GameModeSpectator, hides the player from all
+// other players until it switches back to a visible game mode.
 func (p *Player) SetGameMode(mode world.GameMode) {
 	previous := p.gameMode.Swap(mode)
 	p.session().SendGameMode(mode)
@@ -1253,13 +1745,26 @@ func (p *Player) SetGameMode(mode world.GameMode) {
 		v.ViewEntityGameMode(p)
 	}
 
-	if !mode.AllowsFlying() {
-		p.StopFlying()
+	if !p.CanFly() {
+		p.stopFlying()
 	}
 	if !mode.Visible() {
 		p.SetInvisible()
+		if previous.Visible() {
+			// Players with a game mode that isn't visible are hidden from other players completely, so that
+			// they can't be seen or interacted with.
+			for _, v := range p.viewers() {
+				v.HideEntity(p)
+			}
+		}
 	} else if !previous.Visible() {
 		p.SetVisible()
+		for _, v := range p.viewers() {
+			v.ViewEntity(p)
+			v.ViewEntityState(p)
+			v.ViewEntityItems(p)
+			v.ViewEntityArmour(p)
+		}
 	}
 }
 
@@ -1524,6 +2029,11 @@ func (p *Player) UseItemOnEntity(e world.Entity) bool {
 	if p.Handler().HandleItemUseOnEntity(ctx, e); ctx.Cancelled() {
 		return false
 	}
+	if other, ok := e.(*Player); ok {
+		if other.Handler().HandleInteract(ctx, p); ctx.Cancelled() {
+			return false
+		}
+	}
 	i, left := p.HeldItems()
 	usable, ok := i.Item().(item.UsableOnEntity)
 	if !ok {
@@ -1555,6 +2065,11 @@ func (p *Player) AttackEntity(e world.Entity) bool {
 		critical       = !p.Sprinting() && !p.Flying() && p.FallDistance() > 0 && !slowFalling && !blind
 	)
 
+	if _, ok := e.(*Player); ok && !p.World().PvP() {
+		// PvP is disabled in the world, so the attack is ignored entirely.
+		return false
+	}
+
 	ctx := event.C()
 	if p.Handler().HandleAttackEntity(ctx, e, &force, &height, &critical); ctx.Cancelled() {
 		return false
@@ -1661,11 +2176,45 @@ func (p *Player) StartBreaking(pos cube.Pos, face cube.Face) {
 		return
 	}
 	p.lastBreakDuration = p.breakTime(pos)
+	p.breakProgress, p.breakUpdated = 0, time.Now()
 	for _, viewer := range p.viewers() {
 		viewer.ViewBlockAction(pos, block.StartCrackAction{BreakTime: p.lastBreakDuration})
 	}
 }
 
+// breakLeniency is the duration subtracted from the time a block takes to break when validating if a player
+// broke a block fast enough. It accounts for the delay between packets sent by the client.
+const breakLeniency = time.Millisecond * 150
+
+// updateBreakProgress adds the progress made breaking the current block since the last update to the total
+// progress and sets the time the block takes to break from now on to the duration passed.
+func (p *Player) updateBreakProgress(breakTime time.Duration) {
+	now := time.Now()
+	if p.lastBreakDuration > 0 {
+		p.breakProgress += float64(now.Sub(p.breakUpdated)) / float64(p.lastBreakDuration)
+	} else {
+		p.breakProgress = 1
+	}
+	p.breakUpdated, p.lastBreakDuration = now, breakTime
+}
+
+// brokeTooFast checks if the player finished breaking the block it is currently breaking before the block
+// could have been broken in survival mode, accounting for changes in the break time during breaking.
+func (p *Player) brokeTooFast(pos cube.Pos) bool {
+	if p.GameMode().CreativeInventory() {
+		return false
+	}
+	held, _ := p.HeldItems()
+	if block.BreaksInstantly(p.World().Block(pos), held) {
+		return false
+	}
+	p.updateBreakProgress(p.lastBreakDuration)
+	if p.lastBreakDuration <= breakLeniency {
+		return false
+	}
+	return p.breakProgress+float64(breakLeniency)/float64(p.lastBreakDuration) < 1
+}
+
 // breakTime returns the time needed to break a block at the position passed, taking into account the item
 // held, if the player is on the ground/underwater and if the player has any effects.
 func (p *Player) breakTime(pos cube.Pos) time.Duration {
@@ -1694,14 +2243,23 @@ func (p *Player) breakTime(pos cube.Pos) time.Duration {
 
 // FinishBreaking makes the player finish breaking the block it is currently breaking, or returns immediately
 // if the player isn't breaking anything.
-// FinishBreaking will stop the animation and break the block.
+// FinishBreaking will stop the animation and break the block. If the player is not in creative mode and
+// finished breaking the block faster than the block could be broken with the item held, the block is not
+// broken and is resent to the player instead.
 func (p *Player) FinishBreaking() {
 	pos := p.breakingPos.Load()
 	if !p.breaking.Load() {
 		p.resendBlock(pos, p.World())
 		return
 	}
+	tooFast := p.brokeTooFast(pos)
 	p.AbortBreaking()
+	if tooFast {
+		// The block was broken faster than possible, either due to the client being out of sync or the
+		// client cheating, so we resend the block instead of breaking it.
+		p.resendBlock(pos, p.World())
+		return
+	}
 	p.BreakBlock(pos)
 }
 
@@ -1744,7 +2302,7 @@ func (p *Player) ContinueBreaking(face cube.Face) {
 		for _, viewer := range p.viewers() {
 			viewer.ViewBlockAction(pos, block.ContinueCrackAction{BreakTime: breakTime})
 		}
-		p.lastBreakDuration = breakTime
+		p.updateBreakProgress(breakTime)
 	}
 }
 
@@ -1765,7 +2323,7 @@ func (p *Player) PlaceBlock(pos cube.Pos, b world.Block, ctx *item.UseContext) {
 // of the player. A bool is returned indicating if a block was placed successfully.
 func (p *Player) placeBlock(pos cube.Pos, b world.Block, ignoreBBox bool) bool {
 	w := p.World()
-	if !p.canReach(pos.Vec3Centre()) || !p.GameMode().AllowsEditing() {
+	if !p.canReach(pos.Vec3Centre()) || !p.GameMode().AllowsEditing() || p.protected(w, pos) {
 		p.resendBlocks(pos, w, cube.Faces()...)
 		return false
 	}
@@ -1785,6 +2343,13 @@ func (p *Player) placeBlock(pos cube.Pos, b world.Block, ignoreBBox bool) bool {
 	return true
 }
 
+// protected checks if the player is prevented from editing the block at the position passed because it is outside
+// the build height of the world, or because it is protected by the world, for example by a protected
+// world.Region. Operators may edit protected blocks, but not blocks outside the build height.
+func (p *Player) protected(w *world.World, pos cube.Pos) bool {
+	return pos.OutOfBounds(w.Range()) || (!p.Operator() && w.Protected(pos))
+}
+
 // obstructedPos checks if the position passed is obstructed if the block passed is attempted to be placed.
 // The function returns true if there is an entity in the way that could prevent the block from being placed.
 func (p *Player) obstructedPos(pos cube.Pos, b world.Block) bool {
@@ -1817,7 +2382,7 @@ func (p *Player) BreakBlock(pos cube.Pos) {
 		// Don't do anything if the position broken is already air.
 		return
 	}
-	if !p.canReach(pos.Vec3Centre()) || !p.GameMode().AllowsEditing() {
+	if !p.canReach(pos.Vec3Centre()) || !p.GameMode().AllowsEditing() || p.protected(w, pos) {
 		p.resendBlocks(pos, w)
 		return
 	}
@@ -1959,17 +2524,64 @@ func (p *Player) Teleport(pos mgl64.Vec3) {
 	p.teleport(pos)
 }
 
+// TeleportToPlayer teleports the player to the position of another player, taking over its rotation. If the
+// other player is in a different world, the player is first moved to that world. TeleportToPlayer returns
+// false if the other player is no longer in a world, for example because it disconnected, or if the teleport
+// was cancelled by the Handler of the player, in which case the player is left untouched.
+func (p *Player) TeleportToPlayer(other *Player) bool {
+	w := other.World()
+	if other == p || w == nil {
+		return false
+	}
+	pos, rot := other.Position(), other.Rotation()
+	ctx := event.C()
+	if p.Handler().HandleTeleport(ctx, pos); ctx.Cancelled() {
+		return false
+	}
+	p.yaw.Store(rot.Yaw())
+	p.pitch.Store(rot.Pitch())
+	if w != p.World() {
+		// Move the player before adding it to the new world, so that it is spawned at the target position
+		// rather than at its position in the old world.
+		p.pos.Store(pos)
+		w.AddEntity(p)
+	}
+	p.teleport(pos)
+	return true
+}
+
 // teleport teleports the player to a target position in the world. It does not call the Handler of the
 // player.
 func (p *Player) teleport(pos mgl64.Vec3) {
 	for _, v := range p.viewers() {
 		v.ViewEntityTeleport(p, pos)
 	}
-	p.pos.Store(pos)
+	before := p.pos.Swap(pos)
+	p.updateRegions(p.World(), before, pos)
 	p.vel.Store(mgl64.Vec3{})
 	p.ResetFallDistance()
 }
 
+// updateRegions calls the Handler of the player for every world.Region of the world passed that the player
+// entered or left by moving from one position to another.
+func (p *Player) updateRegions(w *world.World, from, to mgl64.Vec3) {
+	before, after := cube.PosFromVec3(from), cube.PosFromVec3(to)
+	if before == after {
+		return
+	}
+	left, entered := w.RegionsAt(before), w.RegionsAt(after)
+	for _, name := range left {
+		if r, ok := w.Region(name); ok && !r.Within(after) {
+			p.Handler().HandleRegionLeave(name, r)
+		}
+	}
+	for _, name := range entered {
+		if r, ok := w.Region(name); ok && !r.Within(before) {
+			p.Handler().HandleRegionEnter(name, r)
+		}
+	}
+}
+
 // Move moves the player from one position to another in the world, by adding the delta passed to the current
 // position of the player.
 // Move also rotates the player, adding deltaYaw and deltaPitch to the respective values.
@@ -1985,12 +2597,17 @@ func (p *Player) Move(deltaPos mgl64.Vec3, deltaYaw, deltaPitch float64) {
 		// Still update rotation if it was changed.
 		deltaPos = mgl64.Vec3{}
 	}
+	p.resetIdle()
 	var (
 		w                     = p.World()
 		pos                   = p.Position()
 		yaw, pitch            = p.Rotation().Elem()
 		res, resYaw, resPitch = pos.Add(deltaPos), yaw + deltaYaw, pitch + deltaPitch
 	)
+	if p.session() != session.Nop && p.checkSpeed(deltaPos) {
+		p.teleport(pos)
+		return
+	}
 	ctx := event.C()
 	if p.Handler().HandleMove(ctx, res, resYaw, resPitch); ctx.Cancelled() {
 		if p.session() != session.Nop && pos.ApproxEqual(p.Position()) {
@@ -2004,12 +2621,19 @@ func (p *Player) Move(deltaPos mgl64.Vec3, deltaYaw, deltaPitch float64) {
 		v.ViewEntityMovement(p, res, resYaw, resPitch, p.OnGround())
 	}
 
+	p.stateMu.Lock()
 	p.pos.Store(res)
 	p.yaw.Store(resYaw)
 	p.pitch.Store(resPitch)
 	if deltaPos.Len() <= 3 {
 		// Only update velocity if the player is not moving too fast to prevent potential OOMs.
 		p.vel.Store(deltaPos)
+	}
+	p.onGround.Store(p.checkOnGround(w))
+	p.stateMu.Unlock()
+
+	p.updateRegions(w, pos, res)
+	if deltaPos.Len() <= 3 {
 		p.checkBlockCollisions(deltaPos, w)
 	}
 
@@ -2035,7 +2659,6 @@ func (p *Player) Move(deltaPos mgl64.Vec3, deltaYaw, deltaPitch float64) {
 		p.session().ViewEntityState(p)
 	}
 
-	p.onGround.Store(p.checkOnGround(w))
 	p.updateFallState(deltaPos[1])
 
 	if p.Swimming() {
@@ -2043,6 +2666,7 @@ func (p *Player) Move(deltaPos mgl64.Vec3, deltaYaw, deltaPitch float64) {
 	} else if p.Sprinting() {
 		p.Exhaust(0.1 * horizontalVel.Len())
 	}
+	p.recordMovement(horizontalVel.Len())
 }
 
 // World returns the world that the player is currently in.
@@ -2065,6 +2689,7 @@ func (p *Player) Velocity() mgl64.Vec3 {
 // SetVelocity updates the player's velocity. If there is an attached session, this will just send
 // the velocity to the player session for the player to update.
 func (p *Player) SetVelocity(velocity mgl64.Vec3) {
+	p.velocityAt.Store(time.Now())
 	if p.session() == session.Nop {
 		p.vel.Store(velocity)
 		return
@@ -2247,6 +2872,17 @@ func (p *Player) OpenBlockContainer(pos cube.Pos) {
 	}
 }
 
+// OpenInventory opens an inventory that is not backed by a block in the world, such as a menu or a shop, in a
+// chest window. The inventory must have a size of 27. The chest is shown to the player only, at the position
+// passed, which should be close to the player, for example right below it. Items taken from and placed in the
+// inventory may be cancelled using an inventory.Handler, which may be used to create read-only menus.
+// OpenInventory does nothing if the player has no session connected to it.
+func (p *Player) OpenInventory(inv *inventory.Inventory, pos cube.Pos) {
+	if p.session() != session.Nop && inv.Size() == 27 {
+		p.session().OpenInventory(inv, pos)
+	}
+}
+
 // HideEntity hides a world.Entity from the Player so that it can under no circumstance see it. Hidden entities can be
 // made visible again through a call to ShowEntity.
 func (p *Player) HideEntity(e world.Entity) {
@@ -2274,6 +2910,37 @@ func (p *Player) Latency() time.Duration {
 	return p.session().Latency()
 }
 
+// EntityRuntimeID returns the runtime ID that the client of the Player uses to refer to the world.Entity
+// passed, for example in packets sent to the client directly. The Player itself always has the runtime ID 1
+// for its own client. Other entities have runtime IDs that are unique to the client of the Player, so the
+// same entity may have different runtime IDs for different players. False is returned if the Player does not
+// have a session or if the entity is not currently shown to it.
+func (p *Player) EntityRuntimeID(e world.Entity) (uint64, bool) {
+	return p.session().EntityRuntimeID(e)
+}
+
+// Flushes returns the number of times packets sent to the Player have been flushed to its connection. It may
+// be used to tune the flushing of packets with many players online.
+// If the Player does not have a session associated with it, Flushes returns 0.
+func (p *Player) Flushes() uint64 {
+	return p.session().Flushes()
+}
+
+// SendPacket sends a packet.Packet directly to the client of the Player. It should only be used for features
+// not otherwise supported by dragonfly, as packets conflicting with the state held by the server may lead to
+// unexpected behaviour on the client side. If the Player does not have a session associated with it,
+// SendPacket does nothing.
+func (p *Player) SendPacket(pk packet.Packet) {
+	p.session().WritePacket(pk)
+}
+
+// OnPacket sets a function that is called for every packet sent by the client of the Player, before it is
+// handled. If f returns true, the packet is not handled by the server itself. Passing nil removes a function
+// previously set. If the Player does not have a session associated with it, OnPacket does nothing.
+func (p *Player) OnPacket(f func(pk packet.Packet) bool) {
+	p.session().OnPacket(f)
+}
+
 // Tick ticks the entity, performing actions such as checking if the player is still breaking a block.
 func (p *Player) Tick(w *world.World, current int64) {
 	if p.Dead() {
@@ -2295,20 +2962,35 @@ func (p *Player) Tick(w *world.World, current int64) {
 			d := p.damageItem(p.Armour().Chestplate(), 1)
 			p.armour.SetChestplate(d)
 			if d.Durability() < 2 {
-				p.StopGliding()
+				p.stopGliding()
 			}
 		}
 	}
 
 	p.checkBlockCollisions(p.vel.Load(), w)
+	p.stateMu.Lock()
+	p.lastTick.Store(current)
 	p.onGround.Store(p.checkOnGround(w))
+	p.stateMu.Unlock()
 
 	p.effects.Tick(p)
 
 	p.tickFood(w)
 	p.tickAirSupply(w)
-	if p.Position()[1] < float64(w.Range()[0]) && p.GameMode().AllowsTakingDamage() && current%10 == 0 {
-		p.Hurt(4, entity.VoidDamageSource{})
+	if p.Sprinting() {
+		p.statsMu.Lock()
+		p.stats.SprintTime += time.Second / 20
+		p.statsMu.Unlock()
+	}
+	if current%20 == 0 {
+		p.tickIdle()
+	}
+	if y, teleport := w.VoidLevel(); p.Position()[1] < float64(y) {
+		if teleport {
+			p.Teleport(w.Spawn().Vec3Middle())
+		} else if p.GameMode().AllowsTakingDamage() && current%10 == 0 {
+			p.Hurt(4, entity.VoidDamageSource{})
+		}
 	}
 	if !p.AttackImmune() && p.insideOfSolid(w) {
 		p.Hurt(1, entity.SuffocationDamageSource{})
@@ -2626,6 +3308,31 @@ func (p *Player) OnGround() bool {
 	return p.onGround.Load()
 }
 
+// CollidingWithBlocks checks if the bounding box of the player intersects with the collision boxes of any
+// blocks in its world. It may be used to check if the player is clipping into terrain, for example to validate
+// its movement.
+func (p *Player) CollidingWithBlocks() bool {
+	w := p.World()
+	if w == nil {
+		return false
+	}
+	box := p.Type().BBox(p).Translate(p.Position()).Grow(-0.0001)
+	min, max := cube.PosFromVec3(box.Min()), cube.PosFromVec3(box.Max())
+	for y := min[1]; y <= max[1]; y++ {
+		for x := min[0]; x <= max[0]; x++ {
+			for z := min[2]; z <= max[2]; z++ {
+				pos := cube.Pos{x, y, z}
+				for _, bb := range w.Block(pos).Model().BBox(pos, w) {
+					if bb.Translate(pos.Vec3()).IntersectsWith(box) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
 // EyeHeight returns the eye height of the player: 1.62, or 0.52 if the player is swimming.
 func (p *Player) EyeHeight() float64 {
 	if p.swimming.Load() {
@@ -2634,12 +3341,51 @@ func (p *Player) EyeHeight() float64 {
 	return 1.62
 }
 
+// TargetBlock returns the position of the first block with a collision model that the player is looking at
+// within the distance passed, along with the face of the block that the player is looking at. False is
+// returned if no such block is within the distance, or if the line of sight passes through a chunk that is not
+// loaded before hitting a block.
+func (p *Player) TargetBlock(maxDistance float64) (pos cube.Pos, face cube.Face, ok bool) {
+	if maxDistance <= 0 {
+		return pos, face, false
+	}
+	w := p.World()
+	start := p.Position().Add(mgl64.Vec3{0, p.EyeHeight()})
+	end := start.Add(p.Rotation().Vec3().Mul(maxDistance))
+
+	trace.TraverseBlocks(start, end, func(bp cube.Pos) bool {
+		if !w.ChunkLoaded(world.ChunkPos{int32(bp[0] >> 4), int32(bp[2] >> 4)}) {
+			// Don't load new chunks just to find the target block.
+			return false
+		}
+		if res, hit := trace.BlockIntercept(bp, w, w.Block(bp), start, end); hit {
+			pos, face, ok = bp, res.Face(), true
+			return false
+		}
+		return true
+	})
+	return pos, face, ok
+}
+
 // PlaySound plays a world.Sound that only this Player can hear. Unlike World.PlaySound, it is not broadcast
 // to players around it.
 func (p *Player) PlaySound(sound world.Sound) {
 	p.session().PlaySound(sound)
 }
 
+// PlaySoundAt plays a world.Sound at a position that only this Player can hear. The volume of the sound
+// depends on the distance of the Player to the position. A sound.Custom may be used to play a sound by its
+// name with a specific volume and pitch.
+func (p *Player) PlaySoundAt(pos mgl64.Vec3, sound world.Sound) {
+	p.session().PlaySoundAt(pos, sound)
+}
+
+// StopSound stops a sound playing for the Player by its name, such as a looping sound or music played using a
+// sound.Custom. If name is empty, all sounds playing for the Player are stopped.
+func (p *Player) StopSound(name string) {
+	p.session().StopSound(name)
+}
+
 // ShowParticle shows a particle that only this Player can see. Unlike World.AddParticle, it is not broadcast
 // to players around it.
 func (p *Player) ShowParticle(pos mgl64.Vec3, particle world.Particle) {
@@ -2694,6 +3440,22 @@ func (p *Player) SwingArm() {
 	}
 }
 
+// Emote makes the player perform the emote with the UUID passed. The emote is shown to all viewers of the player,
+// including the player itself.
+func (p *Player) Emote(emote uuid.UUID) {
+	for _, v := range p.viewers() {
+		v.ViewEmote(p, emote)
+	}
+}
+
+// OnSpawn adds a function that is called once the client of the player has received the chunks around its
+// spawn position and signalled that it has spawned in the world. Logic that should run when a player joins,
+// such as teleporting it or giving it items, should generally be run using OnSpawn to make sure the client
+// is fully loaded. If the player has already spawned or has no client, f is called immediately.
+func (p *Player) OnSpawn(f func()) {
+	p.session().OnSpawn(f)
+}
+
 // PunchAir makes the player punch the air and plays the sound for attacking with no damage.
 func (p *Player) PunchAir() {
 	if p.Dead() {
@@ -2752,31 +3514,39 @@ func (p *Player) addNewItem(ctx *item.UseContext) {
 		p.Drop(ctx.NewItem.Grow(ctx.NewItem.Count() - n))
 	}
 	if p.Dead() {
-		p.dropContents()
+		p.dropContents(p.deathDrops())
 	}
 }
 
 // canReach checks if a player can reach a position with its current range. The range depends on if the player
-// is either survival or creative mode.
+// is either survival or creative mode and on the reach set using SetReach. A margin is added to the range to
+// account for the latency of the player.
 func (p *Player) canReach(pos mgl64.Vec3) bool {
 	const (
 		creativeRange = 14.0
-		survivalRange = 8.0
+		// sprintSpeed is the approximate maximum distance in blocks a player can move per second without
+		// flying. It is used to compensate for the latency of the player.
+		sprintSpeed = 5.6
+		// maxLatencyMargin is the maximum margin added to the range because of the latency of the player.
+		maxLatencyMargin = 2.0
 	)
 	if !p.GameMode().AllowsInteraction() {
 		return false
 	}
 	eyes := entity.EyePosition(p)
+	margin := math.Min(p.Latency().Seconds()*sprintSpeed, maxLatencyMargin)
 
 	if p.GameMode().CreativeInventory() {
-		return eyes.Sub(pos).Len() <= creativeRange && !p.Dead()
+		return eyes.Sub(pos).Len() <= creativeRange+margin && !p.Dead()
 	}
-	return eyes.Sub(pos).Len() <= survivalRange && !p.Dead()
+	return eyes.Sub(pos).Len() <= p.Reach()+margin && !p.Dead()
 }
 
 // Disconnect closes the player and removes it from the world.
 // Disconnect, unlike Close, allows a custom message to be passed to show to the player when it is
 // disconnected. The message is formatted following the rules of fmt.Sprintln without a newline at the end.
+// If no message is passed, the connection is closed without showing a disconnection screen to the player,
+// which may be used to drop connections, for example of bots, silently.
 func (p *Player) Disconnect(msg ...any) {
 	p.once.Do(func() {
 		p.close(format(msg))
@@ -2801,7 +3571,12 @@ func (p *Player) close(msg string) {
 	if p.Dead() && p.session() != nil {
 		p.Respawn()
 	}
-	p.h.Swap(NopHandler{}).HandleQuit()
+	reason, ok := p.session().DisconnectReason()
+	if !ok {
+		// The session isn't closed yet, so the player is being disconnected by the server.
+		reason = session.DisconnectReasonKick
+	}
+	p.h.Swap(NopHandler{}).HandleQuit(reason)
 
 	if s := p.s.Swap(nil); s != nil {
 		s.Disconnect(msg)
@@ -2845,6 +3620,7 @@ func (p *Player) load(data Data) {
 	}
 	p.fireTicks.Store(data.FireTicks)
 	p.fallDistance.Store(data.FallDistance)
+	p.playtime = data.Playtime
 
 	p.loadInventory(data.Inventory)
 	for slot, stack := range data.EnderChestInventory {
@@ -2861,10 +3637,36 @@ func (p *Player) loadInventory(data InventoryData) {
 	p.Armour().Set(data.Helmet, data.Chestplate, data.Leggings, data.Boots)
 }
 
+// SetProvider sets the Provider that the data of the Player is written to when Save is called. The server
+// sets this to its own Provider when the Player joins.
+func (p *Player) SetProvider(prov Provider) {
+	p.provider.Store(prov)
+}
+
+// Save immediately writes the current data of the Player to its Provider, as set using SetProvider, and
+// returns any error that occurred. An error is returned if no Provider was set.
+// Save is safe to call from any goroutine, for example to persist the inventory of the Player before
+// transferring it to another server. The data is collected using Data, which takes the movement state from a
+// Snapshot, so the position saved never holds a partially applied movement. Other fields, such as the
+// inventory, are each read atomically, but may change between being read if the Player is modified
+// concurrently.
+func (p *Player) Save() error {
+	prov := p.provider.Load()
+	if prov == nil {
+		return fmt.Errorf("save player %v: no provider set", p.Name())
+	}
+	if err := prov.Save(p.UUID(), p.Data()); err != nil {
+		return fmt.Errorf("save player %v: %w", p.Name(), err)
+	}
+	return nil
+}
+
 // Data returns the player data that needs to be saved. This is used when the player
 // gets disconnected and the player provider needs to save the data.
+// The position, rotation and health are taken from a single Snapshot of the Player.
 func (p *Player) Data() Data {
-	yaw, pitch := p.Rotation().Elem()
+	snapshot := p.Snapshot()
+	yaw, pitch := snapshot.Rotation.Elem()
 	offHand, _ := p.offHand.Item(0)
 
 	p.hunger.mu.RLock()
@@ -2873,11 +3675,11 @@ func (p *Player) Data() Data {
 	return Data{
 		UUID:            p.UUID(),
 		Username:        p.Name(),
-		Position:        p.Position(),
+		Position:        snapshot.Position,
 		Velocity:        mgl64.Vec3{},
 		Yaw:             yaw,
 		Pitch:           pitch,
-		Health:          p.Health(),
+		Health:          snapshot.Health,
 		MaxHealth:       p.MaxHealth(),
 		Hunger:          p.hunger.foodLevel,
 		Experience:      p.Experience(),
@@ -2903,6 +3705,8 @@ func (p *Player) Data() Data {
 		FireTicks:           p.fireTicks.Load(),
 		FallDistance:        p.fallDistance.Load(),
 		World:               p.World(),
+		Playtime:            p.Playtime(),
+		LastPlayed:          time.Now(),
 	}
 }
 
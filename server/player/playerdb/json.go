@@ -35,6 +35,10 @@ func (p *Provider) fromJson(d jsonData, world func(world.Dimension) *world.World
 		Inventory:           dataToInv(d.Inventory),
 		EnderChestInventory: make([]item.Stack, 27),
 		World:               world(idToDimension(d.Dimension)),
+		Playtime:            time.Duration(d.Playtime) * time.Second,
+	}
+	if d.LastPlayed != 0 {
+		data.LastPlayed = time.Unix(d.LastPlayed, 0)
 	}
 	decodeItems(d.EnderChestInventory, data.EnderChestInventory)
 	return data
@@ -66,6 +70,8 @@ func (p *Provider) toJson(d player.Data) jsonData {
 		Inventory:           invToData(d.Inventory),
 		EnderChestInventory: encodeItems(d.EnderChestInventory),
 		Dimension:           uint8(d.World.Dimension().EncodeDimension()),
+		Playtime:            int64(d.Playtime / time.Second),
+		LastPlayed:          d.LastPlayed.Unix(),
 	}
 }
 
@@ -89,6 +95,7 @@ type jsonData struct {
 	FireTicks                        int64
 	FallDistance                     float64
 	Dimension                        uint8
+	Playtime, LastPlayed             int64
 }
 
 type jsonInventoryData struct {
@@ -13,9 +13,19 @@ import (
 type Scoreboard struct {
 	name    string
 	lines   []string
+	scores  map[int]int
 	padding bool
 }
 
+// Entry is a line of a Scoreboard together with the score shown on the right side of it.
+type Entry struct {
+	// Text is the text shown on the line.
+	Text string
+	// Score is the number shown on the right side of the line. The lines of a scoreboard are sorted by their
+	// score in ascending order.
+	Score int
+}
+
 // New returns a new scoreboard with the display name passed. Once returned, lines may be added to the
 // scoreboard to add text to it. The name is formatted according to the rules of fmt.Sprintln.
 // Changing the scoreboard after sending it to a player will not update the scoreboard of the player
@@ -67,6 +77,32 @@ func (board *Scoreboard) Remove(index int) {
 		panic(fmt.Sprintf("index out of range %v", index))
 	}
 	board.lines = append(board.lines[:index], board.lines[index+1:]...)
+	if board.scores == nil {
+		return
+	}
+	// Move the scores of the lines after the line removed up along with their lines.
+	scores := make(map[int]int, len(board.scores))
+	for i, score := range board.scores {
+		switch {
+		case i < index:
+			scores[i] = score
+		case i > index:
+			scores[i-1] = score
+		}
+	}
+	board.scores = scores
+}
+
+// SetScore changes the score shown on the right side of a specific line in the scoreboard. By default, the score
+// of a line is its index. SetScore panics if the index passed is negative or 15+.
+func (board *Scoreboard) SetScore(index, score int) {
+	if index < 0 || index >= 15 {
+		panic(fmt.Sprintf("index out of range %v", index))
+	}
+	if board.scores == nil {
+		board.scores = make(map[int]int)
+	}
+	board.scores[index] = score
 }
 
 // RemovePadding removes the padding of one space that is added to the start of every line.
@@ -74,6 +110,20 @@ func (board *Scoreboard) RemovePadding() {
 	board.padding = false
 }
 
+// Entries returns the lines of the Scoreboard, as returned by Lines, together with their scores.
+func (board *Scoreboard) Entries() []Entry {
+	lines := board.Lines()
+	entries := make([]Entry, len(lines))
+	for i, line := range lines {
+		score, ok := board.scores[i]
+		if !ok {
+			score = i
+		}
+		entries[i] = Entry{Text: line, Score: score}
+	}
+	return entries
+}
+
 // Lines returns the data of the Scoreboard as a slice of strings.
 func (board *Scoreboard) Lines() []string {
 	lines := slices.Clone(board.lines)
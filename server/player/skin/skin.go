@@ -1,6 +1,8 @@
 package skin
 
 import (
+	"encoding/json"
+	"fmt"
 	"image"
 	"image/color"
 )
@@ -46,6 +48,80 @@ func New(width, height int) Skin {
 	}
 }
 
+// NewFromBytes creates a new skin using the width, height and pixel data passed. An error is returned if the
+// dimensions are not those of a known skin size (64x32, 64x64 or 128x128), or if the length of the pixel data
+// does not match the dimensions. The model name and model are left empty.
+func NewFromBytes(width, height int, pix []uint8) (Skin, error) {
+	if !validSize(width, height) {
+		return Skin{}, fmt.Errorf("invalid skin dimensions %vx%v: must be 64x32, 64x64 or 128x128", width, height)
+	}
+	if len(pix) != width*height*4 {
+		return Skin{}, fmt.Errorf("expected %v bytes of pixel data for a %vx%v skin, got %v", width*height*4, width, height, len(pix))
+	}
+	s := New(width, height)
+	s.Pix = pix
+	return s, nil
+}
+
+// Default returns a plain 64x64 skin using the standard humanoid model. It may be used in place of a skin that
+// could not be used.
+func Default() Skin {
+	s := New(64, 64)
+	for i := 0; i < len(s.Pix); i += 4 {
+		s.Pix[i], s.Pix[i+1], s.Pix[i+2], s.Pix[i+3] = 0x80, 0x80, 0x80, 0xff
+	}
+	s.ModelConfig = ModelConfig{Default: "geometry.humanoid.custom"}
+	return s
+}
+
+// Validate checks if the Skin is valid, so that it may be shown to other players without breaking their
+// rendering. It checks the dimensions and pixel data of the skin and, if a Model is set, checks if it is a
+// valid geometry JSON object and if the ModelConfig refers to a model. An error describing the problem is
+// returned if the Skin is not valid.
+func (s Skin) Validate() error {
+	if !validSize(s.w, s.h) {
+		return fmt.Errorf("invalid skin dimensions %vx%v: must be 64x32, 64x64 or 128x128", s.w, s.h)
+	}
+	if len(s.Pix) != s.w*s.h*4 {
+		return fmt.Errorf("expected %v bytes of pixel data for a %vx%v skin, got %v", s.w*s.h*4, s.w, s.h, len(s.Pix))
+	}
+	if len(s.Model) == 0 {
+		return nil
+	}
+	if s.ModelConfig.Default == "" {
+		return fmt.Errorf("model config has no default model")
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(s.Model, &m); err != nil {
+		return fmt.Errorf("decode model: %w", err)
+	}
+	if geometry, ok := m["minecraft:geometry"]; ok {
+		var models []struct {
+			Description struct {
+				Identifier string `json:"identifier"`
+			} `json:"description"`
+		}
+		if err := json.Unmarshal(geometry, &models); err != nil {
+			return fmt.Errorf("decode model geometry: %w", err)
+		}
+		for i, model := range models {
+			if model.Description.Identifier == "" {
+				return fmt.Errorf("model geometry %v has no identifier", i)
+			}
+		}
+	}
+	return nil
+}
+
+// validSize checks if the width and height passed are the dimensions of a known skin size.
+func validSize(width, height int) bool {
+	switch {
+	case width == 64 && height == 32, width == 64 && height == 64, width == 128 && height == 128:
+		return true
+	}
+	return false
+}
+
 // Bounds returns the bounds of the skin. These are either 64x32, 64x64 or 128, depending on the bounds of the
 // skin of the player.
 func (s Skin) Bounds() image.Rectangle {
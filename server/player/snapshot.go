@@ -0,0 +1,44 @@
+package player
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// Snapshot holds the state of a Player at a single moment, as returned by Player.Snapshot. It may be used by
+// plugins, such as anti-cheats, that need to read multiple fields of the Player for the same tick.
+type Snapshot struct {
+	// Tick is the current tick of the world of the Player when it was last ticked.
+	Tick int64
+	// Position and Velocity are the position and velocity of the Player. The Velocity is always empty for
+	// players that are controlled by a client.
+	Position, Velocity mgl64.Vec3
+	// Rotation is the yaw and pitch of the Player.
+	Rotation cube.Rotation
+	// OnGround, Sneaking, Sprinting, Swimming, Flying and Gliding specify the movement state of the Player.
+	OnGround, Sneaking, Sprinting, Swimming, Flying, Gliding bool
+	// Health is the current health of the Player.
+	Health float64
+}
+
+// Snapshot returns a Snapshot of the current state of the Player. The movement state is captured while holding
+// the same lock that Move and Tick hold while updating it, so the Snapshot never holds a partially applied
+// movement or tick, unlike values obtained through separate calls over time. Snapshot is safe to call from
+// any goroutine, including from within handlers of the Player.
+func (p *Player) Snapshot() Snapshot {
+	p.stateMu.RLock()
+	defer p.stateMu.RUnlock()
+	return Snapshot{
+		Tick:      p.lastTick.Load(),
+		Position:  p.Position(),
+		Velocity:  p.Velocity(),
+		Rotation:  p.Rotation(),
+		OnGround:  p.OnGround(),
+		Sneaking:  p.Sneaking(),
+		Sprinting: p.Sprinting(),
+		Swimming:  p.Swimming(),
+		Flying:    p.Flying(),
+		Gliding:   p.Gliding(),
+		Health:    p.Health(),
+	}
+}
@@ -0,0 +1,79 @@
+package player
+
+import (
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/go-gl/mathgl/mgl64"
+	"time"
+)
+
+// SpeedCheck is the name of the check passed to Handler.HandleFlag when a Player moves faster horizontally than
+// allowed by its maximum move speed, as set using Player.SetMaxMoveSpeed.
+const SpeedCheck = "speed"
+
+// velocityGrace is the duration after the velocity of a Player was last changed by the server, for example by
+// knock-back, during which the speed of the Player is not checked.
+const velocityGrace = time.Second * 2
+
+// SetMaxMoveSpeed sets the maximum horizontal distance in blocks that the Player may move per tick at the default
+// movement speed of 0.1. The distance is scaled by the current Speed of the Player, so that sprinting and the
+// speed effect are accounted for. Every movement is checked separately, so latency does not lead to false
+// positives. Movement while flying or gliding, and shortly after the velocity of the Player was changed, for
+// example by knock-back, is not checked. Movement exceeding the maximum counts as a violation, after which
+// Handler.HandleFlag is called with SpeedCheck and the movement is reverted. Passing 0 disables the check, which
+// is the default.
+func (p *Player) SetMaxMoveSpeed(v float64) {
+	p.maxMoveSpeed.Store(v)
+}
+
+// MaxMoveSpeed returns the maximum horizontal distance that the Player may move per tick at the default movement
+// speed, as set using SetMaxMoveSpeed.
+func (p *Player) MaxMoveSpeed() float64 {
+	return p.maxMoveSpeed.Load()
+}
+
+// Violations returns the amount of times the Player was flagged by the check passed, such as SpeedCheck.
+func (p *Player) Violations(check string) int {
+	p.violationsMu.Lock()
+	defer p.violationsMu.Unlock()
+	return p.violations[check]
+}
+
+// ResetViolations resets the amount of times the Player was flagged by the check passed to zero.
+func (p *Player) ResetViolations(check string) {
+	p.violationsMu.Lock()
+	defer p.violationsMu.Unlock()
+	delete(p.violations, check)
+}
+
+// flag adds a violation of the check passed to the Player and calls Handler.HandleFlag. False is returned if the
+// Handler cancelled the flag, in which case the action checked should be allowed.
+func (p *Player) flag(check string) bool {
+	p.violationsMu.Lock()
+	if p.violations == nil {
+		p.violations = map[string]int{}
+	}
+	p.violations[check]++
+	n := p.violations[check]
+	p.violationsMu.Unlock()
+
+	ctx := event.C()
+	p.Handler().HandleFlag(ctx, check, n)
+	return !ctx.Cancelled()
+}
+
+// checkSpeed checks if the horizontal movement passed exceeds the maximum move speed of the Player. If it does,
+// the Player is flagged and true is returned if the movement should be reverted.
+func (p *Player) checkSpeed(deltaPos mgl64.Vec3) bool {
+	max := p.maxMoveSpeed.Load()
+	if max <= 0 || p.Flying() || p.Gliding() || p.GameMode().AllowsFlying() {
+		return false
+	}
+	if time.Since(p.velocityAt.Load()) < velocityGrace {
+		return false
+	}
+	deltaPos[1] = 0
+	if deltaPos.Len() <= max*p.Speed()/0.1 {
+		return false
+	}
+	return p.flag(SpeedCheck)
+}
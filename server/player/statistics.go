@@ -0,0 +1,50 @@
+package player
+
+import "time"
+
+// Statistics holds movement statistics of a Player, such as the distance it walked and the amount of times it
+// jumped. They are counted from the moment the Player joins or its statistics were last reset using
+// Player.ResetStatistics.
+type Statistics struct {
+	// DistanceWalked is the horizontal distance in blocks that the Player moved while walking or sprinting.
+	// Movement while swimming, flying or gliding is not included.
+	DistanceWalked float64
+	// DistanceSprinted is the part of DistanceWalked that the Player moved while sprinting.
+	DistanceSprinted float64
+	// Jumps is the amount of times the Player jumped off the ground.
+	Jumps int
+	// SprintTime is the total time the Player spent sprinting.
+	SprintTime time.Duration
+}
+
+// Statistics returns the movement Statistics of the player, such as the distance walked and the amount of
+// jumps. Statistics may be used by minigames to track the progress of players.
+func (p *Player) Statistics() Statistics {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+	return p.stats
+}
+
+// ResetStatistics resets all movement Statistics of the player to zero, for example at the start of a round of
+// a minigame.
+func (p *Player) ResetStatistics() {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+	p.stats = Statistics{}
+}
+
+// recordMovement adds a horizontal distance moved by the player to its Statistics. Distances moved while
+// swimming, flying or gliding, and distances too large to be regular movement, are ignored.
+func (p *Player) recordMovement(dist float64) {
+	if dist > 3 || p.Swimming() || p.Flying() || p.Gliding() {
+		return
+	}
+	sprinting := p.Sprinting()
+
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+	p.stats.DistanceWalked += dist
+	if sprinting {
+		p.stats.DistanceSprinted += dist
+	}
+}
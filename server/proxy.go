@@ -0,0 +1,79 @@
+package server
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server/session"
+	"net"
+)
+
+// proxyConn is a session.Conn of a connection made through a trusted proxy. Its
+// RemoteAddr returns the address of the client connected to the proxy rather
+// than the address of the proxy itself.
+type proxyConn struct {
+	session.Conn
+	addr net.Addr
+}
+
+// RemoteAddr returns the address of the client connected to the proxy.
+func (c proxyConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+// resolveProxy returns a session.Conn with the address of the client behind a
+// proxy if the connection passed was made through one of the TrustedProxies of
+// the Config and ProxyAddr returns the address of the client. Otherwise, the
+// connection passed is returned.
+func (srv *Server) resolveProxy(c session.Conn) session.Conn {
+	if srv.conf.ProxyAddr == nil || !srv.trustedProxy(c.RemoteAddr()) {
+		return c
+	}
+	addr, ok := srv.conf.ProxyAddr(c)
+	if !ok {
+		return c
+	}
+	srv.conf.Log.Debugf("connection %v forwarded by trusted proxy %v", addr, c.RemoteAddr())
+	return proxyConn{Conn: c, addr: addr}
+}
+
+// trustedProxy checks if the net.Addr passed is that of one of the
+// TrustedProxies of the Config.
+func (srv *Server) trustedProxy(addr net.Addr) bool {
+	host := addr.String()
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, network := range srv.proxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrustedProxies parses the IP addresses and CIDR ranges passed, as found
+// in the TrustedProxies of a Config. Single IP addresses are returned as a
+// network holding only that address. An error is returned if any of the
+// entries is neither an IP address nor a CIDR range.
+func parseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			networks = append(networks, network)
+			continue
+		}
+		ip := net.ParseIP(proxy)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: must be an IP address or CIDR range", proxy)
+		}
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return networks, nil
+}
@@ -5,14 +5,18 @@ import (
 	"context"
 	_ "embed"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"github.com/df-mc/atomic"
+	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/event"
 	"github.com/df-mc/dragonfly/server/internal/iteminternal"
 	"github.com/df-mc/dragonfly/server/internal/sliceutil"
 	_ "github.com/df-mc/dragonfly/server/item" // Imported for maintaining correct initialisation order.
 	"github.com/df-mc/dragonfly/server/player"
 	"github.com/df-mc/dragonfly/server/player/skin"
+	"github.com/df-mc/dragonfly/server/player/title"
 	"github.com/df-mc/dragonfly/server/session"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl32"
@@ -26,36 +30,65 @@ import (
 	"github.com/sandertv/gophertunnel/minecraft/text"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"math"
 	"math/rand"
+	"net"
 	"os"
 	"os/exec"
 	"os/signal"
 	"runtime"
+	"runtime/debug"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
+	_ "unsafe" // Imported for compiler directives.
 )
 
 // Server implements a Dragonfly server. It runs the main server loop and
 // handles the connections of players trying to join the server.
 type Server struct {
-	conf Config
+	// confMu guards the fields of conf that may be changed by calling
+	// Server.Reload.
+	confMu sync.RWMutex
+	conf   Config
 
 	once    sync.Once
 	started atomic.Bool
+	name    atomic.Value[string]
+	status  atomic.Value[func() ServerStatus]
 
 	world, nether, end *world.World
 
-	customItems []protocol.ItemComponentEntry
+	customBlocks []protocol.BlockEntry
+	customItems  []protocol.ItemComponentEntry
 
 	listeners []Listener
 	incoming  chan *session.Session
+	closing   chan struct{}
+	limiter   *connLimiter
+	proxies   []*net.IPNet
+	ops       *operatorList
 
 	pmu sync.RWMutex
 	// p holds a map of all players currently connected to the server. When they
 	// leave, they are removed from the map.
 	p map[uuid.UUID]*player.Player
+	// pcond is signalled every time a player is removed from p or a connection
+	// finished joining. It uses pmu as its Locker.
+	pcond *sync.Cond
+	// queue holds the connections waiting for a free slot while the server is
+	// full, in the order they connected. joining is the amount of connections
+	// admitted that are still spawning. Both are guarded by pmu.
+	queue   []session.Conn
+	joining int
+	// s holds the sessions of the players in p.
+	s map[uuid.UUID]*session.Session
+	// lagging is true while the TPS of the server is below the
+	// LowTPSThreshold of the Config.
+	lagging atomic.Bool
 	// pwg is a sync.WaitGroup used to wait for all players to be disconnected
 	// before server shutdown, so that their data is saved properly.
 	pwg sync.WaitGroup
@@ -79,15 +112,42 @@ func New() *Server {
 // Listen starts running the server's listeners but does not block, unlike Run.
 // Connections will be accepted on a different goroutine until the listeners
 // are closed using a call to Close. Once started, players may be accepted
-// using Server.Accept().
+// using Server.Accept(). Listen panics if the Server was already started and
+// ends the program if one of the listeners could not be created. Start may be
+// used to handle these errors instead.
 func (srv *Server) Listen() {
-	if !srv.started.CAS(false, true) {
+	if err := srv.Start(); errors.Is(err, ErrAlreadyStarted) {
 		panic("start server: already started")
+	} else if err != nil {
+		srv.conf.Log.Fatalf("start server: %v", err)
+	}
+}
+
+// Start starts running the server's listeners like Listen, but returns an
+// error instead of panicking or ending the program if the server could not be
+// started. ErrAlreadyStarted is returned if the Server was already started,
+// and a ListenError if one of its listeners could not be created. The
+// ListenError matches ErrAddressInUse using errors.Is if the address of the
+// listener was already in use. In that case, the listeners already created
+// are closed again, and Start may be called again.
+func (srv *Server) Start() error {
+	if !srv.started.CAS(false, true) {
+		return ErrAlreadyStarted
 	}
 
 	srv.conf.Log.Infof("Starting Dragonfly for Minecraft v%v...", protocol.CurrentVersion)
-	srv.startListening()
+	if err := srv.startListening(); err != nil {
+		srv.started.Store(false)
+		return err
+	}
 	go srv.wait()
+	if srv.conf.AutosaveInterval > 0 {
+		go srv.autoSave()
+	}
+	if srv.conf.LowTPSThreshold > 0 {
+		go srv.monitorTPS()
+	}
+	return nil
 }
 
 // Accept accepts an incoming player into the server. It blocks until a player
@@ -108,6 +168,7 @@ func (srv *Server) Accept(f HandleFunc) bool {
 
 	srv.pmu.Lock()
 	srv.p[p.UUID()] = p
+	srv.s[p.UUID()] = s
 	srv.pmu.Unlock()
 
 	s.Start()
@@ -133,15 +194,248 @@ func (srv *Server) End() *world.World {
 	return srv.end
 }
 
+// Name returns the current name of the server, as shown in the server list
+// and in the in-game menu.
+func (srv *Server) Name() string {
+	return srv.name.Load()
+}
+
+// SetName changes the name of the server. The new name is shown in the server
+// list within a few seconds and in the in-game menu of players that join
+// afterwards. Minecraft colour codes may be used in the name.
+func (srv *Server) SetName(name string) {
+	srv.name.Store(name)
+}
+
+// Reload applies the Config passed to the running Server without closing its
+// Listeners or disconnecting players. The Name, MaxPlayers, QueueSize,
+// Allower, AcceptedVersions and ShutdownMessage are applied immediately, as
+// are the Messages shown to connections that are refused. The JoinMessage,
+// QuitMessage, idle message, ChatCooldown, PacketPolicy, ChatFormat,
+// IdleTimeout, MaxReach, MaxMoveSpeed, EntityTrackingRange and the welcome
+// messages are applied to players that join afterwards, and the LowTPS limits
+// once the Server starts or stops lagging behind. Changes to the Difficulty,
+// DisablePvP, SpawnProtectionRadius, ProtectBedrock, VoidLevel and
+// VoidTeleport are applied to the worlds of the Server, and XUIDs added to or
+// removed from Operators are made or are no longer operator.
+// Listeners are never recreated, as closing a Listener disconnects all players
+// connected through it. Players are therefore neither transferred nor warned
+// when the address of a Listener changes: If the amount of Listeners changed,
+// or the addresses they listen on changed for a Config created using
+// UserConfig.Config, the Listeners are reported in the error returned and a
+// restart is required.
+// The fields passed that differ from the current Config but cannot be changed
+// at runtime are ignored and listed in the error returned. ProxyAddr and
+// PanicFunc are only reported if they are set or unset. Fields holding other
+// implementations, such as Log, StatusProvider, Resources, EventSink,
+// PlayerProvider, WorldProvider, Generator and Entities, cannot be compared
+// and are always ignored.
+func (srv *Server) Reload(c Config) error {
+	if c.Name == "" {
+		c.Name = "Dragonfly Server"
+	}
+	if c.Allower == nil {
+		c.Allower = allower{}
+	}
+	if c.LoginTimeout == 0 {
+		c.LoginTimeout = time.Minute
+	}
+	if c.MaxChunkRadius == 0 {
+		c.MaxChunkRadius = 12
+	}
+	c.MaxChunkRadius = clampChunkRadius(c.MaxChunkRadius)
+	if c.ChunksPerTick <= 0 {
+		c.ChunksPerTick = 4
+	}
+	if c.MaxReach <= 0 {
+		c.MaxReach = 8
+	}
+	if c.ChatFormat == "" {
+		c.ChatFormat = "<%v> %v"
+	}
+	c.Messages = c.Messages.withDefaults()
+
+	srv.confMu.Lock()
+	conf := srv.conf
+
+	var unchanged []string
+	check := func(name string, changed bool) {
+		if changed {
+			unchanged = append(unchanged, name)
+		}
+	}
+	check("Listeners", len(c.Listeners) != len(conf.Listeners) || !slices.Equal(c.addresses, conf.addresses))
+	check("ResourcesRequired", c.ResourcesRequired != conf.ResourcesRequired)
+	check("DisableResourceBuilding", c.DisableResourceBuilding != conf.DisableResourceBuilding)
+	check("AuthDisabled", c.AuthDisabled != conf.AuthDisabled)
+	check("ConnectionsPerSecond", c.ConnectionsPerSecond != conf.ConnectionsPerSecond)
+	check("LoginTimeout", c.LoginTimeout != conf.LoginTimeout)
+	check("ReadTimeout", c.ReadTimeout != conf.ReadTimeout)
+	check("FlushImmediately", c.FlushImmediately != conf.FlushImmediately)
+	check("RejectDuplicateLogins", c.RejectDuplicateLogins != conf.RejectDuplicateLogins)
+	check("MaxChunkRadius", c.MaxChunkRadius != conf.MaxChunkRadius)
+	check("ChunksPerTick", c.ChunksPerTick != conf.ChunksPerTick)
+	check("OperatorsFile", c.OperatorsFile != conf.OperatorsFile)
+	check("ReadOnlyWorld", c.ReadOnlyWorld != conf.ReadOnlyWorld)
+	check("AutosaveInterval", c.AutosaveInterval != conf.AutosaveInterval)
+	check("LowTPSThreshold", c.LowTPSThreshold != conf.LowTPSThreshold)
+	check("ChunkUnloadDelay", c.ChunkUnloadDelay != conf.ChunkUnloadDelay)
+	check("RandomTickSpeed", c.RandomTickSpeed != conf.RandomTickSpeed)
+	check("EntityMergeThreshold", c.EntityMergeThreshold != conf.EntityMergeThreshold)
+	check("MaxChunkEntities", c.MaxChunkEntities != conf.MaxChunkEntities)
+	check("AntiXray", c.AntiXray != conf.AntiXray)
+	check("SpawnAtWorldSpawn", c.SpawnAtWorldSpawn != conf.SpawnAtWorldSpawn)
+	check("RecoverPanics", c.RecoverPanics != conf.RecoverPanics)
+	check("OverworldRange", c.OverworldRange != conf.OverworldRange)
+	check("TrustedProxies", !slices.Equal(c.TrustedProxies, conf.TrustedProxies))
+	check("ProxyAddr", (c.ProxyAddr == nil) != (conf.ProxyAddr == nil))
+	check("PanicFunc", (c.PanicFunc == nil) != (conf.PanicFunc == nil))
+
+	srv.name.Store(c.Name)
+	srv.conf.Name, srv.conf.MaxPlayers, srv.conf.Allower = c.Name, c.MaxPlayers, c.Allower
+	srv.conf.JoinMessage, srv.conf.QuitMessage, srv.conf.ShutdownMessage = c.JoinMessage, c.QuitMessage, c.ShutdownMessage
+	srv.conf.WelcomeMessage, srv.conf.WelcomeTitle, srv.conf.WelcomeSubtitle = c.WelcomeMessage, c.WelcomeTitle, c.WelcomeSubtitle
+	srv.conf.ChatCooldown, srv.conf.IdleTimeout, srv.conf.MaxReach = c.ChatCooldown, c.IdleTimeout, c.MaxReach
+	srv.conf.PacketPolicy = c.PacketPolicy
+	srv.conf.ChatFormat, srv.conf.Messages, srv.conf.MaxMoveSpeed = c.ChatFormat, c.Messages, c.MaxMoveSpeed
+	srv.conf.LowTPSChunksPerTick, srv.conf.LowTPSEntityTrackingRange = c.LowTPSChunksPerTick, c.LowTPSEntityTrackingRange
+	srv.conf.EntityTrackingRange, srv.conf.AcceptedVersions, srv.conf.QueueSize = c.EntityTrackingRange, c.AcceptedVersions, c.QueueSize
+	srv.conf.Difficulty, srv.conf.DisablePvP, srv.conf.SpawnProtectionRadius = c.Difficulty, c.DisablePvP, c.SpawnProtectionRadius
+	srv.conf.ProtectBedrock, srv.conf.VoidLevel, srv.conf.VoidTeleport = c.ProtectBedrock, c.VoidLevel, c.VoidTeleport
+	srv.conf.Operators = slices.Clone(c.Operators)
+	srv.confMu.Unlock()
+
+	srv.reloadWorlds(conf, c)
+	srv.reloadOperators(conf.Operators, c.Operators)
+
+	// Wake up connections waiting in the queue, as MaxPlayers may have been
+	// raised.
+	srv.pmu.Lock()
+	srv.pcond.Broadcast()
+	srv.pmu.Unlock()
+
+	if len(unchanged) != 0 {
+		return fmt.Errorf("reload: fields cannot be changed at runtime: %v", strings.Join(unchanged, ", "))
+	}
+	return nil
+}
+
+// reloadWorlds applies the fields of the new Config passed that affect the
+// worlds of the Server, if they differ from those of the old Config.
+func (srv *Server) reloadWorlds(old, c Config) {
+	worlds := []*world.World{srv.world, srv.nether, srv.end}
+	for _, w := range worlds {
+		if c.Difficulty != nil && c.Difficulty != old.Difficulty {
+			w.SetDifficulty(c.Difficulty)
+		}
+		if c.DisablePvP != old.DisablePvP {
+			w.SetPvP(!c.DisablePvP)
+		}
+		if c.ProtectBedrock != old.ProtectBedrock {
+			w.SetBedrockProtection(c.ProtectBedrock)
+		}
+		y, _ := w.VoidLevel()
+		if w == srv.world && c.VoidLevel != nil {
+			y = *c.VoidLevel
+		}
+		w.SetVoidLevel(y, c.VoidTeleport)
+	}
+	if c.SpawnProtectionRadius != old.SpawnProtectionRadius {
+		srv.world.SetSpawnProtection(c.SpawnProtectionRadius)
+	}
+}
+
+// reloadOperators makes the XUIDs in the new operators passed that are not in
+// the old operators an operator, and revokes the operator status of XUIDs in
+// the old operators that are no longer in the new ones.
+func (srv *Server) reloadOperators(old, ops []string) {
+	for _, xuid := range ops {
+		if xuid != "" && !slices.Contains(old, xuid) {
+			if err := srv.AddOperator(xuid); err != nil {
+				srv.conf.Log.Errorf("reload: add operator %v: %v", xuid, err)
+			}
+		}
+	}
+	for _, xuid := range old {
+		if !slices.Contains(ops, xuid) {
+			if err := srv.RemoveOperator(xuid); err != nil {
+				srv.conf.Log.Errorf("reload: remove operator %v: %v", xuid, err)
+			}
+		}
+	}
+}
+
+// config returns a copy of the Config of the Server. It must be used to read
+// fields of the Config that may be changed by calling Server.Reload.
+func (srv *Server) config() Config {
+	srv.confMu.RLock()
+	defer srv.confMu.RUnlock()
+	return srv.conf
+}
+
+// PlayerCount returns the current amount of players online on the server.
+func (srv *Server) PlayerCount() int {
+	srv.pmu.RLock()
+	defer srv.pmu.RUnlock()
+	return len(srv.p)
+}
+
+// TPS returns the average amount of ticks per second over the last 5 seconds
+// of the slowest of the overworld, nether and end of the Server. This is 20 if
+// none of the worlds are lagging.
+func (srv *Server) TPS() float64 {
+	return math.Min(srv.world.TPS(), math.Min(srv.nether.TPS(), srv.end.TPS()))
+}
+
+// AddOperator makes the player with the XUID passed an operator of the server.
+// If the player is online, it is given operator permissions immediately. The
+// change is written to the OperatorsFile set in the Config, if any, in which
+// case an error is returned if writing the file failed. ErrEmptyXUID is
+// returned if xuid is empty.
+func (srv *Server) AddOperator(xuid string) error {
+	if xuid == "" {
+		return ErrEmptyXUID
+	}
+	srv.setOperator(xuid, true)
+	return srv.ops.add(xuid)
+}
+
+// RemoveOperator removes the player with the XUID passed from the operators of
+// the server. If the player is online, its operator permissions are revoked
+// immediately. The change is written to the OperatorsFile set in the Config,
+// if any, in which case an error is returned if writing the file failed.
+func (srv *Server) RemoveOperator(xuid string) error {
+	srv.setOperator(xuid, false)
+	return srv.ops.remove(xuid)
+}
+
+// Operators returns the XUIDs of all operators of the server.
+func (srv *Server) Operators() []string {
+	return srv.ops.list()
+}
+
+// setOperator updates the operator status of all online players with the XUID
+// passed. Players without an XUID are never affected.
+func (srv *Server) setOperator(xuid string, op bool) {
+	if xuid == "" {
+		return
+	}
+	for _, p := range srv.Players() {
+		if p.XUID() == xuid {
+			p.SetOperator(op)
+		}
+	}
+}
+
 // MaxPlayerCount returns the maximum amount of players that are allowed to
 // play on the server at the same time. Players trying to join when the server
 // is full will be refused to enter. If the config has a maximum player count
 // set to 0, MaxPlayerCount will return Server.PlayerCount + 1.
 func (srv *Server) MaxPlayerCount() int {
-	if srv.conf.MaxPlayers == 0 {
-		return len(srv.Players()) + 1
+	if max := srv.config().MaxPlayers; max != 0 {
+		return max
 	}
-	return srv.conf.MaxPlayers
+	return srv.PlayerCount() + 1
 }
 
 // Players returns a list of all players currently connected to the server.
@@ -153,6 +447,21 @@ func (srv *Server) Players() []*player.Player {
 	return maps.Values(srv.p)
 }
 
+// ForEachPlayer calls the function passed for every player currently
+// connected to the server, until f returns false. Players cannot join or
+// leave the server while ForEachPlayer is running, so f must not call methods
+// of the Server that add or remove players, and must not disconnect players
+// and wait for them to leave, or a deadlock occurs.
+func (srv *Server) ForEachPlayer(f func(p *player.Player) bool) {
+	srv.pmu.RLock()
+	defer srv.pmu.RUnlock()
+	for _, p := range srv.p {
+		if !f(p) {
+			return
+		}
+	}
+}
+
 // Player looks for a player on the server with the UUID passed. If found, the
 // player is returned and the bool returns holds a true value. If not, the bool
 // returned is false and the player is nil.
@@ -181,6 +490,72 @@ func (srv *Server) PlayerByXUID(xuid string) (*player.Player, bool) {
 	})
 }
 
+// Schedule schedules f to be called once after delay ticks have passed. f is
+// called on the goroutine that ticks the overworld of the Server, so f should
+// not block.
+func (srv *Server) Schedule(delay int64, f func()) {
+	srv.world.Schedule(delay, f)
+}
+
+// ScheduleRepeating schedules f to be called every interval ticks on the
+// goroutine that ticks the overworld of the Server. The function returned
+// must be called to stop calling f.
+func (srv *Server) ScheduleRepeating(interval int64, f func()) (cancel func()) {
+	return srv.world.ScheduleRepeating(interval, f)
+}
+
+// BroadcastTo sends a message to all players online that f returns true for,
+// for example to send messages only to operators. If f panics for a player,
+// the panic is logged and the player does not receive the message, but the
+// message is still sent to the other players.
+func (srv *Server) BroadcastTo(f func(p *player.Player) bool, msg ...any) {
+	for _, p := range srv.Players() {
+		if srv.matches(f, p) {
+			p.Message(msg...)
+		}
+	}
+}
+
+// matches calls f with the player passed and returns the result. If f panics,
+// the panic is recovered and logged and false is returned.
+func (srv *Server) matches(f func(p *player.Player) bool, p *player.Player) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			srv.conf.Log.Errorf("broadcast filter panicked for player %v: %v", p.Name(), r)
+			ok = false
+		}
+	}()
+	return f(p)
+}
+
+// recoverConn recovers a panic in the goroutine handling the connection passed
+// if RecoverPanics is set in the Config, so that only the connection is
+// closed. It must be called using defer.
+func (srv *Server) recoverConn(c session.Conn) {
+	if !srv.conf.RecoverPanics {
+		return
+	}
+	if r := recover(); r != nil {
+		stack := debug.Stack()
+		srv.conf.Log.Errorf("recovered panic handling connection %v: %v\n%s", c.RemoteAddr(), r, stack)
+		_ = c.Close()
+		srv.panicFunc()(r, stack)
+	}
+}
+
+// panicFunc returns the function passed to sessions to handle recovered
+// panics, or nil if RecoverPanics is not set in the Config.
+func (srv *Server) panicFunc() func(v any, stack []byte) {
+	if !srv.conf.RecoverPanics {
+		return nil
+	}
+	return func(v any, stack []byte) {
+		if srv.conf.PanicFunc != nil {
+			srv.conf.PanicFunc(v, stack)
+		}
+	}
+}
+
 // CloseOnProgramEnd closes the server right before the program ends, so that
 // all data of the server are saved properly.
 func (srv *Server) CloseOnProgramEnd() {
@@ -195,11 +570,20 @@ func (srv *Server) CloseOnProgramEnd() {
 }
 
 // Close closes the server, making any call to Run/Accept cancel immediately.
+// ErrServerClosed is returned if the server was already closed, and
+// ErrNotStarted if the server was not yet started or could not be started.
 func (srv *Server) Close() error {
 	if !srv.started.Load() {
-		panic("server not yet running")
+		return ErrNotStarted
+	}
+	closed := true
+	srv.once.Do(func() {
+		closed = false
+		srv.close()
+	})
+	if closed {
+		return ErrServerClosed
 	}
-	srv.once.Do(srv.close)
 	return nil
 }
 
@@ -208,10 +592,11 @@ func (srv *Server) Close() error {
 func (srv *Server) close() {
 	srv.conf.Log.Infof("Server shutting down...")
 	defer srv.conf.Log.Infof("Server stopped.")
+	close(srv.closing)
 
 	srv.conf.Log.Debugf("Disconnecting players...")
 	for _, p := range srv.Players() {
-		p.Disconnect(text.Colourf("<yellow>%v</yellow>", srv.conf.ShutdownMessage))
+		p.Disconnect(text.Colourf("<yellow>%v</yellow>", srv.config().ShutdownMessage))
 	}
 	srv.pwg.Wait()
 
@@ -235,10 +620,63 @@ func (srv *Server) close() {
 	}
 }
 
+// autoSave saves the data of all online players every time the
+// AutosaveInterval of the Config passes, until the Server is closed. Chunks are
+// saved by the worlds themselves at the same interval.
+func (srv *Server) autoSave() {
+	t := time.NewTicker(srv.conf.AutosaveInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			players := srv.Players()
+			for _, p := range players {
+				if err := srv.conf.PlayerProvider.Save(p.UUID(), p.Data()); err != nil {
+					srv.conf.Log.Errorf("Error while saving data: %v", err)
+				}
+			}
+			srv.conf.Log.Debugf("Saved data of %v players.", len(players))
+		case <-srv.closing:
+			return
+		}
+	}
+}
+
+// monitorTPS logs a warning when the TPS of the Server drops below the
+// LowTPSThreshold of the Config, until the Server is closed.
+func (srv *Server) monitorTPS() {
+	t := time.NewTicker(time.Second * 5)
+	defer t.Stop()
+
+	lagging := false
+	for {
+		select {
+		case <-t.C:
+			tps := srv.TPS()
+			if tps < srv.conf.LowTPSThreshold && !lagging {
+				srv.conf.Log.Warnf("Server is lagging behind: running at %.1f ticks per second.", tps)
+			} else if tps >= srv.conf.LowTPSThreshold && lagging {
+				srv.conf.Log.Infof("Server is no longer lagging behind: running at %.1f ticks per second.", tps)
+			}
+			if lagging != (tps < srv.conf.LowTPSThreshold) {
+				lagging = !lagging
+				srv.lagging.Store(lagging)
+				srv.pmu.RLock()
+				for _, s := range srv.s {
+					srv.applyLimits(s)
+				}
+				srv.pmu.RUnlock()
+			}
+		case <-srv.closing:
+			return
+		}
+	}
+}
+
 // listen makes the Server listen for new connections from the Listener passed.
-// This may be used to listen for players on different interfaces. Note that
-// the maximum player count of additional Listeners added is not enforced
-// automatically. The limit must be enforced by the Listener.
+// This may be used to listen for players on different interfaces. The maximum
+// player count of the Server is enforced for connections of all Listeners.
 func (srv *Server) listen(l Listener) {
 	wg := new(sync.WaitGroup)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -246,8 +684,11 @@ func (srv *Server) listen(l Listener) {
 		c, err := l.Accept()
 		if err != nil {
 			// Cancel the context so that any call to StartGameContext is
-			// cancelled rapidly.
+			// cancelled rapidly, and wake up connections waiting in the queue.
 			cancel()
+			srv.pmu.Lock()
+			srv.pcond.Broadcast()
+			srv.pmu.Unlock()
 			// First wait until all connections that are being handled are
 			// done inserting the player into the channel. Afterwards, when
 			// we're sure no more values will be inserted in the players
@@ -256,15 +697,44 @@ func (srv *Server) listen(l Listener) {
 			srv.wg.Done()
 			return
 		}
+		c = srv.resolveProxy(c)
+
+		if !srv.limiter.allow(c.RemoteAddr()) {
+			srv.conf.Log.Debugf("connection %v exceeded connection limit", c.RemoteAddr())
+			_ = l.Disconnect(c, srv.config().Messages.TooManyConnections)
+			continue
+		}
 
+		conf := srv.config()
+		if v := c.ClientData().GameVersion; len(conf.AcceptedVersions) > 0 && !slices.Contains(conf.AcceptedVersions, v) {
+			srv.conf.Log.Infof("Rejected connection from %v (%v): game version %v is not accepted.", c.RemoteAddr(), c.IdentityData().DisplayName, v)
+			msg := conf.Messages.UnsupportedVersion
+			if strings.Contains(msg, "%v") {
+				msg = fmt.Sprintf(msg, v, strings.Join(conf.AcceptedVersions, ", "))
+			}
+			_ = l.Disconnect(c, msg)
+			continue
+		}
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			if msg, ok := srv.conf.Allower.Allow(c.RemoteAddr(), c.IdentityData(), c.ClientData()); !ok {
+			defer srv.recoverConn(c)
+			if msg, ok := conf.Allower.Allow(c.RemoteAddr(), c.IdentityData(), c.ClientData()); !ok {
 				_ = c.WritePacket(&packet.Disconnect{HideDisconnectionScreen: msg == "", Message: msg})
 				_ = c.Close()
 				return
 			}
+			if !srv.admit(ctx, c) {
+				_ = c.WritePacket(&packet.PlayStatus{Status: packet.PlayStatusLoginFailedServerFull})
+				_ = c.Close()
+				return
+			}
+			defer func() {
+				srv.pmu.Lock()
+				srv.joining--
+				srv.pcond.Broadcast()
+				srv.pmu.Unlock()
+			}()
 			srv.finaliseConn(ctx, c, l)
 		}()
 	}
@@ -272,18 +742,75 @@ func (srv *Server) listen(l Listener) {
 
 // startListening starts making the EncodeBlock listener listen, accepting new
 // connections from players.
-func (srv *Server) startListening() {
+func (srv *Server) startListening() error {
+	srv.makeBlockEntries()
 	srv.makeItemComponents()
 
-	srv.wg.Add(len(srv.conf.Listeners))
 	for _, lf := range srv.conf.Listeners {
 		l, err := lf(srv.conf)
 		if err != nil {
-			srv.conf.Log.Fatalf("create listener: %v", err)
+			// Close the listeners that were already created, so that their
+			// addresses are freed again.
+			for _, l := range srv.listeners {
+				_ = l.Close()
+			}
+			srv.listeners = nil
+			return ListenError{Err: err}
 		}
 		srv.listeners = append(srv.listeners, l)
+	}
+	srv.wg.Add(len(srv.listeners))
+	for _, l := range srv.listeners {
 		go srv.listen(l)
 	}
+	return nil
+}
+
+// makeBlockEntries initializes the server's block entries using the registered custom blocks. It allows block
+// entries to be created only once at startup.
+func (srv *Server) makeBlockEntries() {
+	custom := world.CustomBlocks()
+	if len(custom) == 0 {
+		return
+	}
+	// Collect the values of the properties of all states registered for every custom block, so that the client
+	// knows which states the block may have.
+	names := make([]string, 0, len(custom))
+	values := make(map[string]map[string][]any, len(custom))
+	for rid := uint32(0); ; rid++ {
+		b, ok := world.BlockByRuntimeID(rid)
+		if !ok {
+			break
+		}
+		name, properties := b.EncodeBlock()
+		if _, ok := custom[name]; !ok {
+			continue
+		}
+		if _, ok := values[name]; !ok {
+			names, values[name] = append(names, name), map[string][]any{}
+		}
+		for k, v := range properties {
+			if slices.IndexFunc(values[name][k], func(other any) bool { return other == v }) == -1 {
+				values[name][k] = append(values[name][k], v)
+			}
+		}
+	}
+
+	srv.customBlocks = make([]protocol.BlockEntry, 0, len(names))
+	for _, name := range names {
+		properties := make([]map[string]any, 0, len(values[name]))
+		for k, v := range values[name] {
+			properties = append(properties, map[string]any{"name": k, "enum": v})
+		}
+		srv.customBlocks = append(srv.customBlocks, protocol.BlockEntry{
+			Name: name,
+			Properties: map[string]any{
+				"components":    custom[name].Components(),
+				"properties":    properties,
+				"molangVersion": int32(1),
+			},
+		})
+	}
 }
 
 // makeItemComponents initializes the server's item components map using the
@@ -309,17 +836,65 @@ func (srv *Server) wait() {
 	close(srv.incoming)
 }
 
+// admit checks if the session.Conn passed may join the server. If the server
+// is full, the connection is placed in the queue and admit blocks until a slot
+// frees up for it. False is returned if the server and the queue are both
+// full, or if the context passed is cancelled while the connection is queued.
+func (srv *Server) admit(ctx context.Context, c session.Conn) bool {
+	srv.pmu.Lock()
+	defer srv.pmu.Unlock()
+
+	full := func() bool {
+		max := srv.config().MaxPlayers
+		return max != 0 && len(srv.p)+srv.joining >= max
+	}
+	if full() || len(srv.queue) > 0 {
+		if len(srv.queue) >= srv.config().QueueSize {
+			return false
+		}
+		srv.queue = append(srv.queue, c)
+		srv.conf.Log.Debugf("connection %v queued at position %v\n", c.RemoteAddr(), len(srv.queue))
+		for (srv.queue[0] != c || full()) && ctx.Err() == nil {
+			srv.pcond.Wait()
+		}
+		i := slices.IndexFunc(srv.queue, func(other session.Conn) bool { return other == c })
+		srv.queue = slices.Delete(srv.queue, i, i+1)
+		// Wake up the other connections in the queue, so that the next one
+		// can check if it may join.
+		srv.pcond.Broadcast()
+		if ctx.Err() != nil {
+			return false
+		}
+	}
+	srv.joining++
+	return true
+}
+
 // finaliseConn finalises the session.Conn passed and subtracts from the
 // sync.WaitGroup once done.
 func (srv *Server) finaliseConn(ctx context.Context, conn session.Conn, l Listener) {
-	id := uuid.MustParse(conn.IdentityData().Identity)
+	id := identityUUID(conn.IdentityData())
+	if p, ok := srv.Player(id); ok {
+		if srv.conf.RejectDuplicateLogins {
+			_ = l.Disconnect(conn, srv.config().Messages.AlreadyLoggedIn)
+			return
+		}
+		// Disconnect the player already online and wait for its session to be
+		// closed completely, so that its data is saved before the data of the
+		// new connection is loaded.
+		p.Disconnect(srv.config().Messages.LoggedInElsewhere)
+		srv.waitForClose(p)
+	}
 	data := srv.defaultGameData()
 
 	var playerData *player.Data
 	if d, err := srv.conf.PlayerProvider.Load(id, srv.dimension); err == nil {
-		if d.World == nil {
+		if d.World == nil || srv.conf.SpawnAtWorldSpawn {
 			d.World = srv.world
 		}
+		if srv.conf.SpawnAtWorldSpawn {
+			d.Position = srv.world.Spawn().Vec3Middle()
+		}
 		data.PlayerPosition = vec64To32(d.Position).Add(mgl32.Vec3{0, 1.62})
 		data.Dimension = int32(d.World.Dimension().EncodeDimension())
 		data.Yaw, data.Pitch = float32(d.Yaw), float32(d.Pitch)
@@ -327,19 +902,52 @@ func (srv *Server) finaliseConn(ctx context.Context, conn session.Conn, l Listen
 		playerData = &d
 	}
 
+	ctx, cancel := context.WithTimeout(ctx, srv.conf.LoginTimeout)
+	defer cancel()
 	if err := conn.StartGameContext(ctx, data); err != nil {
-		_ = l.Disconnect(conn, "Connection timeout.")
+		_ = l.Disconnect(conn, srv.config().Messages.LoginTimeout)
 
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			srv.conf.Log.Debugf("connection %v failed spawning: login took longer than %v\n", conn.RemoteAddr(), srv.conf.LoginTimeout)
+			return
+		}
 		srv.conf.Log.Debugf("connection %v failed spawning: %v\n", conn.RemoteAddr(), err)
 		return
 	}
 	_ = conn.WritePacket(&packet.ItemComponent{Items: srv.customItems})
-	if p, ok := srv.Player(id); ok {
-		p.Disconnect("Logged in from another location.")
+	if r := srv.conf.OverworldRange; r != (cube.Range{}) {
+		// The client assumes the default range of the overworld unless told
+		// otherwise through a dimension definition.
+		_ = conn.WritePacket(&packet.DimensionData{Definitions: []protocol.DimensionDefinition{{
+			Name:      "minecraft:overworld",
+			Range:     [2]int32{int32(r[0]), int32(r[1] + 1)},
+			Generator: protocol.GeneratorOverworld,
+		}}})
 	}
 	srv.incoming <- srv.createPlayer(id, conn, playerData)
 }
 
+// identityUUID returns the UUID of the login.IdentityData passed. If the
+// identity does not hold a valid UUID, which may happen for connections of
+// Listeners that do not authenticate or validate players, a UUID is derived
+// from the name of the player, so that it remains stable across sessions.
+func identityUUID(data login.IdentityData) uuid.UUID {
+	if id, err := uuid.Parse(data.Identity); err == nil && id != uuid.Nil {
+		return id
+	}
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte("OfflinePlayer:"+data.DisplayName))
+}
+
+// waitForClose blocks until the player passed is removed from the Server after
+// its session was closed. Players are only removed once their data was saved.
+func (srv *Server) waitForClose(p *player.Player) {
+	srv.pmu.Lock()
+	defer srv.pmu.Unlock()
+	for srv.p[p.UUID()] == p {
+		srv.pcond.Wait()
+	}
+}
+
 // defaultGameData returns a minecraft.GameData as sent for a new player. It
 // may later be modified if the player was saved in the player provider of the
 // server.
@@ -349,9 +957,10 @@ func (srv *Server) defaultGameData() minecraft.GameData {
 		EntityUniqueID:  1,
 		EntityRuntimeID: 1,
 
-		WorldName:       srv.conf.Name,
+		WorldName:       srv.Name(),
 		BaseGameVersion: protocol.CurrentVersion,
 
+		WorldSeed:  srv.world.Seed(),
 		Time:       int64(srv.world.Time()),
 		Difficulty: 2,
 
@@ -359,8 +968,9 @@ func (srv *Server) defaultGameData() minecraft.GameData {
 		PlayerPermissions: packet.PermissionLevelMember,
 		PlayerPosition:    vec64To32(srv.world.Spawn().Vec3Centre().Add(mgl64.Vec3{0, 1.62})),
 
-		Items:     srv.itemEntries(),
-		GameRules: []protocol.GameRule{{Name: "naturalregeneration", Value: false}},
+		Items:        srv.itemEntries(),
+		CustomBlocks: srv.customBlocks,
+		GameRules:    []protocol.GameRule{{Name: "naturalregeneration", Value: false}},
 
 		ServerAuthoritativeInventory: true,
 		PlayerMovementSettings: protocol.PlayerMovementSettings{
@@ -400,20 +1010,35 @@ func (srv *Server) checkNetIsolation() {
 
 // handleSessionClose handles the closing of a session. It removes the player
 // of the session from the server.
-func (srv *Server) handleSessionClose(c session.Controllable) {
-	srv.pmu.Lock()
-	p, ok := srv.p[c.UUID()]
-	delete(srv.p, c.UUID())
-	srv.pmu.Unlock()
+func (srv *Server) handleSessionClose(c session.Controllable, reason session.DisconnectReason) {
+	srv.conf.Log.Debugf("Player %v disconnected (%v).", c.Name(), reason)
+	srv.conf.EventSink.Write(event.Record{Time: time.Now(), Type: event.RecordQuit, Name: c.Name(), XUID: c.XUID(), Reason: reason.String()})
+	srv.pmu.RLock()
+	other, ok := srv.p[c.UUID()]
+	srv.pmu.RUnlock()
 	if !ok {
 		// When a player disconnects immediately after a session is started, it might not be added to the players map
 		// yet. This is expected, but we need to be careful not to crash when this happens.
 		return
 	}
+	p := c.(*player.Player)
 
+	// The data is saved before the player is removed, so that a connection
+	// with the same UUID waiting in waitForClose only loads it once saved.
 	if err := srv.conf.PlayerProvider.Save(p.UUID(), p.Data()); err != nil {
 		srv.conf.Log.Errorf("Error while saving data: %v", err)
 	}
+	if session.Controllable(other) == c {
+		srv.pmu.Lock()
+		// Only remove the player from the map if it wasn't replaced by a player
+		// with the same UUID that joined later.
+		if session.Controllable(srv.p[c.UUID()]) == c {
+			delete(srv.p, c.UUID())
+			delete(srv.s, c.UUID())
+			srv.pcond.Broadcast()
+		}
+		srv.pmu.Unlock()
+	}
 	srv.pwg.Done()
 }
 
@@ -424,14 +1049,72 @@ func (srv *Server) createPlayer(id uuid.UUID, conn session.Conn, data *player.Da
 	if data != nil {
 		w, gm, pos = data.World, data.GameMode, data.Position
 	}
-	s := session.New(conn, srv.conf.MaxChunkRadius, srv.conf.Log, srv.conf.JoinMessage, srv.conf.QuitMessage)
-	p := player.NewWithSession(conn.IdentityData().DisplayName, conn.IdentityData().XUID, id, srv.parseSkin(conn.ClientData()), s, pos, data)
+	conf := srv.config()
+	s := session.Config{
+		Log:              conf.Log,
+		MaxChunkRadius:   conf.MaxChunkRadius,
+		ChunksPerTick:    conf.ChunksPerTick,
+		JoinMessage:      conf.JoinMessage,
+		QuitMessage:      conf.QuitMessage,
+		ChatCooldown:     conf.ChatCooldown,
+		PacketPolicy:     conf.PacketPolicy,
+		EventSink:        conf.EventSink,
+		ReadTimeout:      conf.ReadTimeout,
+		FlushImmediately: conf.FlushImmediately,
+		PanicFunc:        srv.panicFunc(),
+	}.New(conn)
+	p := player.NewWithSession(conn.IdentityData().DisplayName, conn.IdentityData().XUID, id, srv.parseSkin(conn.ClientData(), conn.IdentityData().XUID), s, pos, data)
+
+	p.SetIdleTimeout(conf.IdleTimeout)
+	p.SetIdleMessage(conf.Messages.Idle)
+	p.SetProvider(srv.conf.PlayerProvider)
+	p.SetReach(conf.MaxReach)
+	p.SetMaxMoveSpeed(conf.MaxMoveSpeed)
+	p.SetChatFormat(conf.ChatFormat)
+	srv.applyLimits(s)
+	p.OnSpawn(func() {
+		srv.welcome(p, conf)
+	})
 
+	srv.conf.EventSink.Write(event.Record{Time: time.Now(), Type: event.RecordJoin, Name: p.Name(), XUID: p.XUID()})
 	s.Spawn(p, pos, w, gm, srv.handleSessionClose)
+	// Operator status is applied after spawning, as changing it sends the
+	// abilities of the player to the session, which requires it to be spawned.
+	p.SetOperator(srv.ops.contains(p.XUID()))
 	srv.pwg.Add(1)
 	return s
 }
 
+// welcome sends the WelcomeMessage, WelcomeTitle and WelcomeSubtitle of the
+// Config passed to a player that just spawned.
+func (srv *Server) welcome(p *player.Player, conf Config) {
+	r := strings.NewReplacer("{name}", p.Name(), "{online}", strconv.Itoa(srv.PlayerCount()))
+	if conf.WelcomeMessage != "" {
+		p.Message(text.Colourf("%v", r.Replace(conf.WelcomeMessage)))
+	}
+	if conf.WelcomeTitle != "" || conf.WelcomeSubtitle != "" {
+		p.SendTitle(title.New(text.Colourf("%v", r.Replace(conf.WelcomeTitle))).WithSubtitle(text.Colourf("%v", r.Replace(conf.WelcomeSubtitle))))
+	}
+}
+
+// applyLimits sets the chunks sent per tick and the entity tracking range of
+// the session.Session passed, using the LowTPS values of the Config if the
+// server is lagging behind.
+func (srv *Server) applyLimits(s *session.Session) {
+	conf := srv.config()
+	chunks, r := conf.ChunksPerTick, conf.EntityTrackingRange
+	if srv.lagging.Load() {
+		if conf.LowTPSChunksPerTick > 0 {
+			chunks = conf.LowTPSChunksPerTick
+		}
+		if conf.LowTPSEntityTrackingRange > 0 {
+			r = conf.LowTPSEntityTrackingRange
+		}
+	}
+	s.SetChunksPerTick(chunks)
+	s.SetEntityTrackingRange(r)
+}
+
 // createWorld loads a world of the server with a specific dimension, ending
 // the program if the world could not be loaded. The layers passed are used to
 // create a generator.Flat that is used as generator for the world.
@@ -448,13 +1131,17 @@ func (srv *Server) createWorld(dim world.Dimension, nether, end **world.World) *
 	logger.Debugf("Loading world...")
 
 	conf := world.Config{
-		Log:             logger,
-		Dim:             dim,
-		Provider:        srv.conf.WorldProvider,
-		Generator:       srv.conf.Generator(dim),
-		RandomTickSpeed: srv.conf.RandomTickSpeed,
-		ReadOnly:        srv.conf.ReadOnlyWorld,
-		Entities:        srv.conf.Entities,
+		Log:                  logger,
+		Dim:                  dim,
+		Provider:             srv.conf.WorldProvider,
+		Generator:            srv.conf.Generator(dim),
+		RandomTickSpeed:      srv.conf.RandomTickSpeed,
+		ReadOnly:             srv.conf.ReadOnlyWorld,
+		SaveInterval:         srv.conf.AutosaveInterval,
+		ChunkUnloadDelay:     srv.conf.ChunkUnloadDelay,
+		Entities:             srv.conf.Entities,
+		EntityMergeThreshold: srv.conf.EntityMergeThreshold,
+		MaxChunkEntities:     srv.conf.MaxChunkEntities,
 		PortalDestination: func(dim world.Dimension) *world.World {
 			if dim == world.Nether {
 				return *nether
@@ -464,13 +1151,22 @@ func (srv *Server) createWorld(dim world.Dimension, nether, end **world.World) *
 			return nil
 		},
 	}
+	if r := srv.conf.OverworldRange; dim.EncodeDimension() == 0 && r != (cube.Range{}) {
+		conf.Dim = world.OverworldWithRange(r)
+	}
+	if srv.conf.AntiXray {
+		conf.HiddenBlocks = hiddenBlocks(dim)
+	}
 	w := conf.New()
-	logger.Infof(`Opened world "%v".`, w.Name())
+	logger.Infof(`Opened world "%v" (seed %v).`, w.Name(), w.Seed())
 	return w
 }
 
-// parseSkin parses a skin from the login.ClientData  and returns it.
-func (srv *Server) parseSkin(data login.ClientData) skin.Skin {
+// parseSkin parses a skin from the login.ClientData  and returns it. If the
+// skin has invalid dimensions, the offending player's XUID is logged and
+// skin.Default is returned instead. If only the geometry of the skin is
+// invalid, the skin is kept, but the standard humanoid model is used.
+func (srv *Server) parseSkin(data login.ClientData, xuid string) skin.Skin {
 	// Gophertunnel guarantees the following values are valid data and are of
 	// the correct size.
 	skinData, _ := base64.StdEncoding.DecodeString(data.SkinData)
@@ -479,9 +1175,15 @@ func (srv *Server) parseSkin(data login.ClientData) skin.Skin {
 	skinResourcePatch, _ := base64.StdEncoding.DecodeString(data.SkinResourcePatch)
 	modelConfig, _ := skin.DecodeModelConfig(skinResourcePatch)
 
-	playerSkin := skin.New(data.SkinImageWidth, data.SkinImageHeight)
+	playerSkin, err := skin.NewFromBytes(data.SkinImageWidth, data.SkinImageHeight, skinData)
+	if err != nil {
+		// Gophertunnel does not check the dimensions of the skin, so a
+		// modified client could send a skin that breaks rendering for
+		// other players. Fall back to a default skin instead.
+		srv.conf.Log.Infof("Player with XUID %v sent an invalid skin, using default skin: %v", xuid, err)
+		return skin.Default()
+	}
 	playerSkin.Persona = data.PersonaSkin
-	playerSkin.Pix = skinData
 	playerSkin.Model = modelData
 	playerSkin.ModelConfig = modelConfig
 	playerSkin.PlayFabID = data.PlayFabID
@@ -506,7 +1208,11 @@ func (srv *Server) parseSkin(data login.ClientData) skin.Skin {
 
 		playerSkin.Animations = append(playerSkin.Animations, anim)
 	}
-
+	if err := playerSkin.Validate(); err != nil {
+		srv.conf.Log.Warnf("Player with XUID %v sent an invalid skin geometry, using default model: %v", xuid, err)
+		playerSkin.Model = nil
+		playerSkin.ModelConfig = skin.ModelConfig{Default: "geometry.humanoid.custom"}
+	}
 	return playerSkin
 }
 
@@ -518,6 +1224,11 @@ func (srv *Server) registerTargetFunc() {
 	})
 }
 
+// noinspection ALL
+//
+//go:linkname world_finaliseBlockRegistry github.com/df-mc/dragonfly/server/world.finaliseBlockRegistry
+func world_finaliseBlockRegistry()
+
 // vec64To32 converts a mgl64.Vec3 to a mgl32.Vec3.
 func vec64To32(vec3 mgl64.Vec3) mgl32.Vec3 {
 	return mgl32.Vec3{float32(vec3[0]), float32(vec3[1]), float32(vec3[2])}
@@ -0,0 +1,51 @@
+package session
+
+import (
+	"time"
+)
+
+// ChatCooldown limits the rate at which the client of a Session may send chat messages. The zero value of
+// ChatCooldown does not limit chat messages at all.
+type ChatCooldown struct {
+	// Messages is the maximum amount of chat messages that may be sent within Window. Messages sent beyond
+	// this limit are dropped. If Messages is 0, the amount of messages is not limited.
+	Messages int
+	// Window is the duration of the sliding window in which at most Messages chat messages may be sent. It is
+	// also the duration within which a repeated message is dropped if BlockRepeated is true.
+	Window time.Duration
+	// BlockRepeated specifies if a chat message identical to the previous chat message should be dropped if
+	// it was sent within Window.
+	BlockRepeated bool
+}
+
+// chatLimiter keeps track of the chat messages recently sent by the client of a Session to enforce a
+// ChatCooldown.
+type chatLimiter struct {
+	conf ChatCooldown
+	// sent holds the times at which the most recent chat messages were sent, oldest first. It holds at most
+	// conf.Messages entries.
+	sent        []time.Time
+	lastMessage string
+}
+
+// allow checks if the chat message passed may be sent. If not, the reason the message was dropped is
+// returned. allow returns an empty string if the message may be sent, in which case it is recorded.
+func (c *chatLimiter) allow(message string) string {
+	now := time.Now()
+	if c.conf.BlockRepeated && len(c.sent) > 0 && message == c.lastMessage && now.Sub(c.sent[len(c.sent)-1]) < c.conf.Window {
+		return "§cYou may not send the same message repeatedly."
+	}
+	if c.conf.Messages > 0 {
+		if len(c.sent) >= c.conf.Messages && now.Sub(c.sent[0]) < c.conf.Window {
+			return "§cYou are sending messages too quickly."
+		}
+		if len(c.sent) >= c.conf.Messages {
+			c.sent = c.sent[1:]
+		}
+	} else {
+		// Without a message limit, we only need to track the last message for repetition.
+		c.sent = c.sent[:0]
+	}
+	c.sent, c.lastMessage = append(c.sent, now), message
+	return ""
+}
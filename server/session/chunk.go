@@ -17,6 +17,7 @@ const subChunkRequests = false
 
 // ViewChunk ...
 func (s *Session) ViewChunk(pos world.ChunkPos, c *chunk.Chunk, blockEntities map[cube.Pos]world.Block) {
+	s.forgetRevealed(pos)
 	if !s.conn.ClientCacheEnabled() {
 		s.sendNetworkChunk(pos, c, blockEntities)
 		return
@@ -0,0 +1,102 @@
+package session
+
+import (
+	"github.com/df-mc/atomic"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/item/inventory"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// Config may be used to create a new Session. It holds a variety of fields that influence the Session.
+type Config struct {
+	// Log is the Logger that will be used to log errors and debug messages to. If set to nil, a Logrus logger will be
+	// used.
+	Log Logger
+	// MaxChunkRadius is the maximum chunk radius that the client of the Session may request. If set to 0, a maximum
+	// chunk radius of 12 is used.
+	MaxChunkRadius int
+	// ChunksPerTick is the maximum amount of chunks sent to the client every tick, so that large chunk radii are spread
+	// over multiple ticks. If set to 0, 4 chunks are sent every tick.
+	ChunksPerTick int
+	// JoinMessage and QuitMessage are the messages broadcast when the player of the Session joins or quits. They may
+	// hold '{name}' and '{online}' or '%v', which are replaced with the name of the player and the amount of players
+	// online. If empty, no message is broadcast.
+	JoinMessage, QuitMessage string
+	// ChatCooldown limits the rate at which the client may send chat messages.
+	ChatCooldown ChatCooldown
+	// PacketPolicy specifies how packets from the client that are invalid or unexpected are dealt with.
+	PacketPolicy PacketPolicy
+	// EventSink is the event.Sink that records of the Session, such as chat messages, are written to. If set to nil,
+	// records are discarded.
+	EventSink event.Sink
+	// ReadTimeout is the duration after which the client is disconnected if it has not sent any packets. If set to 0,
+	// the client is only disconnected once the underlying connection times out.
+	ReadTimeout time.Duration
+	// FlushImmediately specifies if packets written are flushed directly after being written, instead of once every
+	// tick.
+	FlushImmediately bool
+	// PanicFunc is called after a panic in the goroutines of the Session, for example in a player.Handler, was
+	// recovered and logged, after which the Session is closed. If set to nil, panics are not recovered and crash the
+	// program.
+	PanicFunc func(v any, stack []byte)
+}
+
+// New returns a new Session using the Config conf and the connection passed. The Session will control a
+// Controllable entity using the packets received from the connection, which it starts handling after a call to
+// Session.Spawn.
+func (conf Config) New(conn Conn) *Session {
+	if conf.Log == nil {
+		conf.Log = logrus.New()
+	}
+	if conf.MaxChunkRadius <= 0 {
+		conf.MaxChunkRadius = 12
+	}
+	if conf.ChunksPerTick <= 0 {
+		conf.ChunksPerTick = 4
+	}
+	if conf.EventSink == nil {
+		conf.EventSink = event.NopSink{}
+	}
+	r := conn.ChunkRadius()
+	if r > conf.MaxChunkRadius {
+		r = conf.MaxChunkRadius
+		_ = conn.WritePacket(&packet.ChunkRadiusUpdated{ChunkRadius: int32(r)})
+	}
+
+	s := &Session{}
+	*s = Session{
+		openChunkTransactions:  make([]map[uint64]struct{}, 0, 8),
+		closeBackground:        make(chan struct{}),
+		ui:                     inventory.New(53, s.handleInterfaceUpdate),
+		handlers:               map[uint32]packetHandler{},
+		entityRuntimeIDs:       map[world.Entity]uint64{},
+		revealed:               map[world.ChunkPos]map[cube.Pos]struct{}{},
+		entities:               map[uint64]world.Entity{},
+		hiddenEntities:         map[world.Entity]struct{}{},
+		outOfRange:             map[world.Entity]struct{}{},
+		blobs:                  map[uint64][]byte{},
+		maxChunkRadius:         int32(conf.MaxChunkRadius),
+		conn:                   conn,
+		log:                    conf.Log,
+		currentEntityRuntimeID: 1,
+		heldSlot:               atomic.NewUint32(0),
+		joinMessage:            conf.JoinMessage,
+		quitMessage:            conf.QuitMessage,
+		chat:                   &chatLimiter{conf: conf.ChatCooldown},
+		packets:                conf.PacketPolicy,
+		sink:                   conf.EventSink,
+		readTimeout:            conf.ReadTimeout,
+		panicFunc:              conf.PanicFunc,
+		flushImmediately:       conf.FlushImmediately,
+		openedWindow:           *atomic.NewValue(inventory.New(1, nil)),
+		chunksPerTick:          *atomic.NewInt64(int64(conf.ChunksPerTick)),
+		chunkRadius:            *atomic.NewInt32(int32(r)),
+	}
+
+	s.registerHandlers()
+	return s
+}
@@ -27,16 +27,19 @@ type Controllable interface {
 	chat.Subscriber
 
 	Locale() language.Tag
+	RequestChunkRadius(radius int)
 
 	SetHeldItems(right, left item.Stack)
 
 	Move(deltaPos mgl64.Vec3, deltaYaw, deltaPitch float64)
 	Speed() float64
+	FlySpeed() float64
 
 	Chat(msg ...any)
 	ExecuteCommand(commandLine string)
 	GameMode() world.GameMode
 	SetGameMode(mode world.GameMode)
+	Operator() bool
 	Effects() []effect.Effect
 
 	UseItem()
@@ -70,6 +73,7 @@ type Controllable interface {
 	StopSwimming()
 	StartFlying()
 	Flying() bool
+	CanFly() bool
 	StopFlying()
 	StartGliding()
 	Gliding() bool
@@ -0,0 +1,55 @@
+package session
+
+// DisconnectReason specifies the reason a Session was closed, derived from the way its connection ended.
+type DisconnectReason int
+
+const (
+	// DisconnectReasonQuit means the client closed the connection, usually because the player left the game.
+	DisconnectReasonQuit DisconnectReason = iota
+	// DisconnectReasonTimeout means no packets were received from the client for too long, for example because
+	// the game crashed or the player lost their internet connection.
+	DisconnectReasonTimeout
+	// DisconnectReasonKick means the server disconnected the client, for example using Player.Disconnect or
+	// because the server was closed.
+	DisconnectReasonKick
+	// DisconnectReasonTransfer means the client left after being transferred to another server.
+	DisconnectReasonTransfer
+)
+
+// String returns a human-readable representation of the DisconnectReason.
+func (r DisconnectReason) String() string {
+	switch r {
+	case DisconnectReasonQuit:
+		return "quit"
+	case DisconnectReasonTimeout:
+		return "timeout"
+	case DisconnectReasonKick:
+		return "kick"
+	case DisconnectReasonTransfer:
+		return "transfer"
+	}
+	panic("should never happen")
+}
+
+// DisconnectReason returns the reason the Session was closed. If the Session is not yet being closed, false
+// is returned.
+func (s *Session) DisconnectReason() (DisconnectReason, bool) {
+	if s == Nop {
+		return 0, false
+	}
+	s.reasonMu.Lock()
+	defer s.reasonMu.Unlock()
+	return s.reason, s.reasonSet
+}
+
+// setDisconnectReason sets the reason the Session was closed, if no reason was set before.
+func (s *Session) setDisconnectReason(r DisconnectReason) {
+	if s == Nop {
+		return
+	}
+	s.reasonMu.Lock()
+	defer s.reasonMu.Unlock()
+	if !s.reasonSet {
+		s.reason, s.reasonSet = r, true
+	}
+}
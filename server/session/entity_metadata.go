@@ -25,48 +25,48 @@ func (s *Session) parseEntityMetadata(e world.Entity) protocol.EntityMetadata {
 	m[protocol.EntityDataKeyEffectAmbience] = byte(0)
 	m[protocol.EntityDataKeyColorIndex] = byte(0)
 
-	m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagHasGravity)
-	m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagClimb)
+	setFlag(m, protocol.EntityDataFlagHasGravity)
+	setFlag(m, protocol.EntityDataFlagClimb)
 	if sn, ok := e.(sneaker); ok && sn.Sneaking() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagSneaking)
+		setFlag(m, protocol.EntityDataFlagSneaking)
 	}
 	if sp, ok := e.(sprinter); ok && sp.Sprinting() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagSprinting)
+		setFlag(m, protocol.EntityDataFlagSprinting)
 	}
 	if sw, ok := e.(swimmer); ok && sw.Swimming() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagSwimming)
+		setFlag(m, protocol.EntityDataFlagSwimming)
 	}
 	if gl, ok := e.(glider); ok && gl.Gliding() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagGliding)
+		setFlag(m, protocol.EntityDataFlagGliding)
 	}
 	if b, ok := e.(breather); ok {
 		m[protocol.EntityDataKeyAirSupply] = int16(b.AirSupply().Milliseconds() / 50)
 		m[protocol.EntityDataKeyAirSupplyMax] = int16(b.MaxAirSupply().Milliseconds() / 50)
 		if b.Breathing() {
-			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagBreathing)
+			setFlag(m, protocol.EntityDataFlagBreathing)
 		}
 	}
 	if i, ok := e.(invisible); ok && i.Invisible() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagInvisible)
+		setFlag(m, protocol.EntityDataFlagInvisible)
 	}
 	if i, ok := e.(immobile); ok && i.Immobile() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagNoAI)
+		setFlag(m, protocol.EntityDataFlagNoAI)
 	}
 	if o, ok := e.(onFire); ok && o.OnFireDuration() > 0 {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagOnFire)
+		setFlag(m, protocol.EntityDataFlagOnFire)
 	}
 	if u, ok := e.(using); ok && u.UsingItem() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagUsingItem)
+		setFlag(m, protocol.EntityDataFlagUsingItem)
 	}
 	if c, ok := e.(arrow); ok && c.Critical() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagCritical)
+		setFlag(m, protocol.EntityDataFlagCritical)
 	}
 	if g, ok := e.(gameMode); ok {
 		if g.GameMode().HasCollision() {
-			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagHasCollision)
+			setFlag(m, protocol.EntityDataFlagHasCollision)
 		}
 		if !g.GameMode().Visible() {
-			m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagInvisible)
+			setFlag(m, protocol.EntityDataFlagInvisible)
 		}
 	}
 	if o, ok := e.(orb); ok {
@@ -85,13 +85,13 @@ func (s *Session) parseEntityMetadata(e world.Entity) protocol.EntityMetadata {
 	}
 	if t, ok := e.(tnt); ok {
 		m[protocol.EntityDataKeyFuseTime] = int32(t.Fuse().Milliseconds() / 50)
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagIgnited)
+		setFlag(m, protocol.EntityDataFlagIgnited)
 	}
 	if n, ok := e.(named); ok {
 		m[protocol.EntityDataKeyName] = n.NameTag()
 		m[protocol.EntityDataKeyAlwaysShowNameTag] = uint8(1)
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagAlwaysShowName)
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagShowName)
+		setFlag(m, protocol.EntityDataFlagAlwaysShowName)
+		setFlag(m, protocol.EntityDataFlagShowName)
 	}
 	if sc, ok := e.(scoreTag); ok {
 		m[protocol.EntityDataKeyScore] = sc.ScoreTag()
@@ -127,10 +127,25 @@ func (s *Session) parseEntityMetadata(e world.Entity) protocol.EntityMetadata {
 		}
 	}
 	if g, ok := e.Type().(glint); ok && g.Glint() {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagEnchanted)
+		setFlag(m, protocol.EntityDataFlagEnchanted)
 	}
 	if _, ok := e.Type().(entity.LingeringPotionType); ok {
-		m.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagLingering)
+		setFlag(m, protocol.EntityDataFlagLingering)
+	}
+	if c, ok := e.(customMetadata); ok {
+		values, flags := c.CustomMetadata()
+		for key, value := range values {
+			m[key] = value
+		}
+		for index, enabled := range flags {
+			key := uint32(protocol.EntityDataKeyFlags)
+			if index >= 64 {
+				key, index = protocol.EntityDataKeyFlagsTwo, index-64
+			}
+			if m.Flag(key, index) != enabled {
+				m.SetFlag(key, index)
+			}
+		}
 	}
 	if eff, ok := e.(effectBearer); ok && len(eff.Effects()) > 0 {
 		visibleEffects := make([]effect.Effect, 0, len(eff.Effects()))
@@ -152,6 +167,18 @@ func (s *Session) parseEntityMetadata(e world.Entity) protocol.EntityMetadata {
 	return m
 }
 
+// setFlag sets the flag with the index passed in the entity flags of the metadata passed. Unlike
+// protocol.EntityMetadata.SetFlag, which toggles the flag, setFlag leaves the flag set if it was already set.
+func setFlag(m protocol.EntityMetadata, index uint8) {
+	if !m.Flag(protocol.EntityDataKeyFlags, index) {
+		m.SetFlag(protocol.EntityDataKeyFlags, index)
+	}
+}
+
+type customMetadata interface {
+	CustomMetadata() (values map[uint32]any, flags map[uint8]bool)
+}
+
 type sneaker interface {
 	Sneaking() bool
 }
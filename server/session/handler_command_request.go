@@ -2,6 +2,7 @@ package session
 
 import (
 	"fmt"
+	"github.com/df-mc/dragonfly/server/event"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
@@ -19,6 +20,7 @@ func (h *CommandRequestHandler) Handle(p packet.Packet, s *Session) error {
 	}
 
 	h.origin = pk.CommandOrigin
+	s.record(event.RecordCommand, pk.CommandLine)
 	s.c.ExecuteCommand(pk.CommandLine)
 	return nil
 }
@@ -33,21 +33,44 @@ func (h *InventoryTransactionHandler) Handle(p packet.Packet, s *Session) error
 		if err := s.UpdateHeldSlot(int(data.HotBarSlot), stackToItem(data.HeldItem.Stack)); err != nil {
 			return err
 		}
+		if !h.verifyHeldItem(data.HeldItem, s) {
+			return nil
+		}
 		return h.handleUseItemOnEntityTransaction(data, s)
 	case *protocol.UseItemTransactionData:
 		if err := s.UpdateHeldSlot(int(data.HotBarSlot), stackToItem(data.HeldItem.Stack)); err != nil {
 			return err
 		}
+		if !h.verifyHeldItem(data.HeldItem, s) {
+			return nil
+		}
 		return h.handleUseItemTransaction(data, s)
 	case *protocol.ReleaseItemTransactionData:
 		if err := s.UpdateHeldSlot(int(data.HotBarSlot), stackToItem(data.HeldItem.Stack)); err != nil {
 			return err
 		}
+		if !h.verifyHeldItem(data.HeldItem, s) {
+			return nil
+		}
 		return h.handleReleaseItemTransaction(s)
 	}
 	return fmt.Errorf("unhandled inventory transaction type %T", pk.TransactionData)
 }
 
+// verifyHeldItem checks if the item the client claims to hold in a transaction is the item held server-side. If
+// not, the inventories are resent to the client so that it is synchronised again, and false is returned, in
+// which case the transaction should be ignored. The count and durability of the items are not compared, as the
+// client may briefly be out of sync with those, for example right after using an item.
+func (h *InventoryTransactionHandler) verifyHeldItem(clientHeld protocol.ItemInstance, s *Session) bool {
+	held, _ := s.c.HeldItems()
+	if claimed := stackToItem(clientHeld.Stack); claimed.Empty() != held.Empty() || !claimed.Comparable(held) {
+		s.log.Debugf("failed processing packet from %v (%v): InventoryTransaction: actual held and client held item mismatch: client: %v vs server: %v", s.conn.RemoteAddr(), s.c.Name(), claimed, held)
+		h.resendInventories(s)
+		return false
+	}
+	return true
+}
+
 // resendInventories resends all inventories of the player.
 func (h *InventoryTransactionHandler) resendInventories(s *Session) {
 	s.sendInv(s.inv, protocol.WindowIDInventory)
@@ -1,7 +1,6 @@
 package session
 
 import (
-	"fmt"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
 
@@ -14,7 +13,11 @@ func (PlayerSkinHandler) Handle(p packet.Packet, s *Session) error {
 
 	playerSkin, err := protocolToSkin(pk.Skin)
 	if err != nil {
-		return fmt.Errorf("error decoding skin: %w", err)
+		// Reject the skin change rather than showing a malformed skin to other players, which could break
+		// their rendering.
+		s.log.Debugf("player with XUID %v sent an invalid skin, keeping previous skin: %v", s.conn.IdentityData().XUID, err)
+		s.ViewSkin(s.c)
+		return nil
 	}
 
 	s.c.SetSkin(playerSkin)
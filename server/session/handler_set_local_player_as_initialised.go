@@ -0,0 +1,19 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// SetLocalPlayerAsInitialisedHandler handles the SetLocalPlayerAsInitialised packet.
+type SetLocalPlayerAsInitialisedHandler struct{}
+
+// Handle ...
+func (*SetLocalPlayerAsInitialisedHandler) Handle(p packet.Packet, s *Session) error {
+	pk := p.(*packet.SetLocalPlayerAsInitialised)
+
+	if pk.EntityRuntimeID != selfEntityRuntimeID {
+		return errSelfRuntimeID
+	}
+	s.markSpawned()
+	return nil
+}
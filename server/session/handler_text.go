@@ -2,6 +2,7 @@ package session
 
 import (
 	"fmt"
+	"github.com/df-mc/dragonfly/server/event"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
 
@@ -21,6 +22,11 @@ func (TextHandler) Handle(p packet.Packet, s *Session) error {
 	if pk.XUID != s.conn.IdentityData().XUID {
 		return fmt.Errorf("XUID must be equal to player's XUID")
 	}
+	if reason := s.chat.allow(pk.Message); reason != "" {
+		s.c.Message(reason)
+		return nil
+	}
+	s.record(event.RecordChat, pk.Message)
 	s.c.Chat(pk.Message)
 	return nil
 }
@@ -0,0 +1,111 @@
+package session
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"golang.org/x/exp/maps"
+)
+
+// revealRadius is the radius in blocks around the player in which blocks hidden from it are revealed.
+const revealRadius = 4
+
+// obfuscates checks if blocks hidden by the world.World should be hidden from the client of the Session.
+// Hidden blocks are always shown to operators and players in a game mode with a creative inventory.
+func (s *Session) obfuscates(w *world.World) bool {
+	return w.Obfuscated() && !s.c.Operator() && !s.c.GameMode().CreativeInventory()
+}
+
+// Obfuscated checks if the blocks hidden by the world.World of the Session are hidden from its client.
+func (s *Session) Obfuscated() bool {
+	return s.obfuscates(s.c.World())
+}
+
+// forgetRevealed forgets the blocks revealed in the chunk at the position passed, so that they may be revealed
+// again after the chunk is sent to the client again.
+func (s *Session) forgetRevealed(pos world.ChunkPos) {
+	s.revealMu.Lock()
+	delete(s.revealed, pos)
+	s.revealMu.Unlock()
+}
+
+// queueReveal queues the neighbours of the position passed to be revealed to the client if they are hidden, if
+// the block passed exposes them.
+func (s *Session) queueReveal(pos cube.Pos, b world.Block) {
+	if !world.Exposes(b) {
+		return
+	}
+	s.revealMu.Lock()
+	s.revealQueue = append(s.revealQueue, pos)
+	s.revealMu.Unlock()
+}
+
+// revealBlocks reveals blocks hidden from the client that were exposed since the last call, and those close
+// to the player if it moved to a different block since the last call.
+func (s *Session) revealBlocks() {
+	s.revealMu.Lock()
+	queue := s.revealQueue
+	s.revealQueue = nil
+	s.revealMu.Unlock()
+
+	w := s.c.World()
+	if w == nil || !s.obfuscates(w) {
+		return
+	}
+	r := w.Range()
+	for _, pos := range queue {
+		pos.Neighbours(func(neighbour cube.Pos) {
+			s.reveal(w, neighbour)
+		}, r)
+	}
+
+	pos := cube.PosFromVec3(s.c.Position())
+	if pos == s.lastRevealPos {
+		return
+	}
+	s.lastRevealPos = pos
+	s.pruneRevealed()
+	for x := -revealRadius; x <= revealRadius; x++ {
+		for y := -revealRadius; y <= revealRadius; y++ {
+			for z := -revealRadius; z <= revealRadius; z++ {
+				if p := pos.Add(cube.Pos{x, y, z}); !p.OutOfBounds(r) {
+					s.reveal(w, p)
+				}
+			}
+		}
+	}
+}
+
+// reveal sends the block at the position passed to the client if it is hidden by the world.World and was not
+// yet revealed to it since its chunk was last sent.
+func (s *Session) reveal(w *world.World, pos cube.Pos) {
+	chunkPos := world.ChunkPos{int32(pos[0] >> 4), int32(pos[2] >> 4)}
+	s.revealMu.Lock()
+	_, revealed := s.revealed[chunkPos][pos]
+	s.revealMu.Unlock()
+	if revealed {
+		return
+	}
+	if b := w.Block(pos); w.Hides(b) {
+		s.ViewBlockUpdate(pos, b, 0)
+
+		s.revealMu.Lock()
+		if s.revealed[chunkPos] == nil {
+			s.revealed[chunkPos] = make(map[cube.Pos]struct{})
+		}
+		s.revealed[chunkPos][pos] = struct{}{}
+		s.revealMu.Unlock()
+	}
+}
+
+// pruneRevealed forgets the blocks revealed in chunks that are no longer loaded by the client.
+func (s *Session) pruneRevealed() {
+	s.revealMu.Lock()
+	positions := maps.Keys(s.revealed)
+	s.revealMu.Unlock()
+
+	for _, pos := range positions {
+		if _, ok := s.chunkLoader.Chunk(pos); !ok {
+			s.forgetRevealed(pos)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package session
+
+import "errors"
+
+// PacketPolicy specifies how a Session deals with packets from its client that are invalid or unexpected. The
+// zero value of PacketPolicy disconnects the client on the first packet that is invalid, while ignoring
+// packets that the Session does not expect.
+type PacketPolicy struct {
+	// MaxViolations is the number of invalid packets the client may send before it is disconnected. Invalid
+	// packets below this limit are logged and otherwise ignored. If MaxViolations is 0 or 1, the client is
+	// disconnected on the first invalid packet.
+	MaxViolations int
+	// Strict specifies if packets that the Session does not expect to receive from a client are treated as
+	// invalid too. In strict mode, the client is disconnected immediately on any invalid or unexpected
+	// packet, regardless of MaxViolations.
+	Strict bool
+}
+
+// errUnexpectedPacket is returned when handling a packet that the Session does not expect to receive from its
+// client while in strict mode.
+var errUnexpectedPacket = errors.New("unexpected packet")
+
+// violation registers a packet that could not be handled because of the error passed. It returns true if the
+// client should be disconnected following the PacketPolicy of the Session.
+func (s *Session) violation(err error) bool {
+	s.violations++
+	s.log.Debugf("failed processing packet from %v (%v) [%v violations]: %v\n", s.conn.RemoteAddr(), s.c.Name(), s.violations, err)
+	return s.packets.Strict || s.violations >= s.packets.MaxViolations
+}
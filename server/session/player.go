@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/df-mc/atomic"
 	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/entity"
 	"github.com/df-mc/dragonfly/server/entity/effect"
 	"github.com/df-mc/dragonfly/server/internal/nbtconv"
@@ -50,11 +51,18 @@ func (s *Session) closeCurrentContainer() {
 	if !s.containerOpened.Load() {
 		return
 	}
+	inv := s.openedWindow.Load()
 	s.closeWindow()
 
 	pos := s.openedPos.Load()
 	w := s.c.World()
 	b := w.Block(pos)
+	if s.virtualContainer.CAS(true, false) {
+		// The chest was only sent to the client, so restore the actual block.
+		inv.RemoveViewer(s)
+		s.ViewBlockUpdate(pos, b, 0)
+		return
+	}
 	if container, ok := b.(block.Container); ok {
 		container.RemoveViewer(s, w, pos)
 	} else if enderChest, ok := b.(block.EnderChest); ok {
@@ -91,6 +99,18 @@ func (s *Session) SendRespawn(pos mgl64.Vec3) {
 	})
 }
 
+// SendSpawnPosition sends the spawn position of the Controllable of the session in the dimension passed to the
+// client, so that the client knows where it will respawn.
+func (s *Session) SendSpawnPosition(pos cube.Pos, dim world.Dimension) {
+	blockPos := protocol.BlockPos{int32(pos[0]), int32(pos[1]), int32(pos[2])}
+	s.writePacket(&packet.SetSpawnPosition{
+		SpawnType:     packet.SpawnTypePlayer,
+		Position:      blockPos,
+		Dimension:     int32(dim.EncodeDimension()),
+		SpawnPosition: blockPos,
+	})
+}
+
 // sendRecipes sends the current crafting recipes to the session.
 func (s *Session) sendRecipes() {
 	recipes := make([]protocol.Recipe, 0, len(recipe.Recipes()))
@@ -186,6 +206,9 @@ func (s *Session) invByID(id int32) (*inventory.Inventory, bool) {
 		return s.armour.Inventory(), true
 	case protocol.ContainerLevelEntity:
 		if s.containerOpened.Load() {
+			if s.virtualContainer.Load() {
+				return s.openedWindow.Load(), true
+			}
 			b := s.c.World().Block(s.openedPos.Load())
 			if _, chest := b.(block.Chest); chest {
 				return s.openedWindow.Load(), true
@@ -255,6 +278,7 @@ func (s *Session) invByID(id int32) (*inventory.Inventory, bool) {
 // Disconnect disconnects the client and ultimately closes the session. If the message passed is non-empty,
 // it will be shown to the client.
 func (s *Session) Disconnect(message string) {
+	s.setDisconnectReason(DisconnectReasonKick)
 	if s != Nop {
 		s.writePacket(&packet.Disconnect{
 			HideDisconnectionScreen: message == "",
@@ -264,6 +288,28 @@ func (s *Session) Disconnect(message string) {
 	}
 }
 
+// ChunkRadius returns the chunk radius of the Session: The radius in chunks around the player in which chunks
+// are sent to the client.
+func (s *Session) ChunkRadius() int {
+	return int(s.chunkRadius.Load())
+}
+
+// SetChunkRadius sets the chunk radius of the Session and sends it to the client. The radius is limited to the
+// maximum chunk radius that the Session was created with.
+func (s *Session) SetChunkRadius(radius int) {
+	if s == Nop {
+		return
+	}
+	if radius > int(s.maxChunkRadius) {
+		radius = int(s.maxChunkRadius)
+	} else if radius < 1 {
+		radius = 1
+	}
+	s.chunkRadius.Store(int32(radius))
+	s.chunkLoader.ChangeRadius(radius)
+	s.writePacket(&packet.ChunkRadiusUpdated{ChunkRadius: int32(radius)})
+}
+
 // SendSpeed sends the speed of the player in an UpdateAttributes packet, so that it is updated client-side.
 func (s *Session) SendSpeed(speed float64) {
 	s.writePacket(&packet.UpdateAttributes{
@@ -338,6 +384,7 @@ func (s *Session) SendForm(f form.Form) {
 
 // Transfer transfers the player to a server with the IP and port passed.
 func (s *Session) Transfer(ip net.IP, port int) {
+	s.setDisconnectReason(DisconnectReasonTransfer)
 	s.writePacket(&packet.Transfer{
 		Address: ip.String(),
 		Port:    uint16(port),
@@ -354,10 +401,18 @@ func (s *Session) SendGameMode(mode world.GameMode) {
 	s.sendAbilities()
 }
 
+// SendAbilities sends the abilities and permissions of the Controllable entity of the session to the client.
+func (s *Session) SendAbilities() {
+	if s == Nop || s.c == nil {
+		return
+	}
+	s.sendAbilities()
+}
+
 // sendAbilities sends the abilities of the Controllable entity of the session to the client.
 func (s *Session) sendAbilities() {
 	mode, abilities := s.c.GameMode(), uint32(0)
-	if mode.AllowsFlying() {
+	if s.c.CanFly() {
 		abilities |= protocol.AbilityMayFly
 		if s.c.Flying() {
 			abilities |= protocol.AbilityFlying
@@ -382,16 +437,20 @@ func (s *Session) sendAbilities() {
 	if mode.AllowsInteraction() {
 		abilities |= protocol.AbilityDoorsAndSwitches | protocol.AbilityOpenContainers | protocol.AbilityAttackPlayers | protocol.AbilityAttackMobs
 	}
+	perms, commandPerms := packet.PermissionLevelMember, packet.CommandPermissionLevelNormal
+	if s.c.Operator() {
+		perms, commandPerms = packet.PermissionLevelOperator, packet.CommandPermissionLevelAdmin
+	}
 	s.writePacket(&packet.UpdateAbilities{AbilityData: protocol.AbilityData{
 		EntityUniqueID:     selfEntityRuntimeID,
-		PlayerPermissions:  packet.PermissionLevelMember,
-		CommandPermissions: packet.CommandPermissionLevelNormal,
-		Layers: []protocol.AbilityLayer{ // TODO: Support customization of fly and walk speeds.
+		PlayerPermissions:  uint8(perms),
+		CommandPermissions: uint8(commandPerms),
+		Layers: []protocol.AbilityLayer{
 			{
 				Type:      protocol.AbilityLayerTypeBase,
 				Abilities: protocol.AbilityCount - 1,
 				Values:    abilities,
-				FlySpeed:  protocol.AbilityBaseFlySpeed,
+				FlySpeed:  float32(s.c.FlySpeed()),
 				WalkSpeed: protocol.AbilityBaseWalkSpeed,
 			},
 		},
@@ -472,6 +531,11 @@ func (s *Session) EnableInstantRespawn(enable bool) {
 	s.sendGameRules([]protocol.GameRule{{Name: "doimmediaterespawn", Value: enable}})
 }
 
+// SendFog sends the stack of fog identifiers passed to the player, replacing the fog previously sent.
+func (s *Session) SendFog(stack []string) {
+	s.writePacket(&packet.PlayerFog{Stack: stack})
+}
+
 // addToPlayerList adds the player of a session to the player list of this session. It will be shown in the
 // in-game pause menu screen.
 func (s *Session) addToPlayerList(session *Session) {
@@ -812,23 +876,22 @@ func protocolToSkin(sk protocol.Skin) (s skin.Skin, err error) {
 		return skin.Skin{}, fmt.Errorf("SkinID must not be an empty string")
 	}
 
-	s = skin.New(int(sk.SkinImageWidth), int(sk.SkinImageHeight))
+	if s, err = skin.NewFromBytes(int(sk.SkinImageWidth), int(sk.SkinImageHeight), sk.SkinData); err != nil {
+		return skin.Skin{}, err
+	}
 	s.Persona = sk.PersonaSkin
-	s.Pix = sk.SkinData
 	s.Model = sk.SkinGeometry
 	s.PlayFabID = sk.PlayFabID
 
 	s.Cape = skin.NewCape(int(sk.CapeImageWidth), int(sk.CapeImageHeight))
 	s.Cape.Pix = sk.CapeData
 
-	m := make(map[string]any)
-	if err = json.Unmarshal(sk.SkinGeometry, &m); err != nil {
-		return skin.Skin{}, fmt.Errorf("SkinGeometry was not a valid JSON string: %v", err)
-	}
-
 	if s.ModelConfig, err = skin.DecodeModelConfig(sk.SkinResourcePatch); err != nil {
 		return skin.Skin{}, fmt.Errorf("SkinResourcePatch was not a valid JSON string: %v", err)
 	}
+	if err = s.Validate(); err != nil {
+		return skin.Skin{}, err
+	}
 
 	for _, anim := range sk.Animations {
 		var t skin.AnimationType
@@ -863,6 +926,19 @@ func gameTypeFromMode(mode world.GameMode) int32 {
 	return packet.GameTypeSurvival
 }
 
+// difficultyID returns the difficulty ID of the world.Difficulty passed.
+func difficultyID(d world.Difficulty) int32 {
+	switch d {
+	case world.DifficultyPeaceful:
+		return 0
+	case world.DifficultyEasy:
+		return 1
+	case world.DifficultyHard:
+		return 3
+	}
+	return 2
+}
+
 // The following functions use the go:linkname directive in order to make sure the item.byID and item.toID
 // functions do not need to be exported.
 
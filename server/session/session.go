@@ -7,6 +7,7 @@ import (
 	"github.com/df-mc/atomic"
 	"github.com/df-mc/dragonfly/server/block"
 	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/event"
 	"github.com/df-mc/dragonfly/server/internal/sliceutil"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/item/inventory"
@@ -23,6 +24,9 @@ import (
 	"github.com/sandertv/gophertunnel/minecraft/text"
 	"io"
 	"net"
+	"runtime/debug"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -39,13 +43,47 @@ type Session struct {
 
 	// onStop is called when the session is stopped. The controllable passed is the controllable that the
 	// session controls.
-	onStop func(controllable Controllable)
+	onStop func(controllable Controllable, reason DisconnectReason)
+	// reason is the reason the session was closed. It is only valid if reasonSet is true.
+	reasonMu  sync.Mutex
+	reason    DisconnectReason
+	reasonSet bool
+	// readTimeout is the duration after which the connection is closed if no packets were received. lastPacket
+	// holds the time at which the last packet was received, in Unix nanoseconds.
+	readTimeout time.Duration
+	lastPacket  atomic.Int64
+	// panicFunc is called with panics recovered in the goroutines of the Session. If nil, panics are not
+	// recovered.
+	panicFunc func(v any, stack []byte)
+	// flushImmediately specifies if packets are flushed directly after being written, rather than once every
+	// tick. flushes holds the number of times the connection was flushed by the Session.
+	flushImmediately bool
+	flushes          atomic.Uint64
+	// onPacket is called for every packet read from the connection before it
+	// is handled. If it returns true, the packet is not handled by the Session.
+	onPacket atomic.Value[func(pk packet.Packet) bool]
+	// spawned specifies if the client has signalled that it finished loading the world and spawned. onSpawn
+	// holds the functions to call once this happens.
+	spawnMu sync.Mutex
+	spawned bool
+	onSpawn []func()
 
 	currentScoreboard atomic.Value[string]
 	currentLines      atomic.Value[[]string]
 
-	chunkLoader                 *world.Loader
-	chunkRadius, maxChunkRadius int32
+	chunkLoader    *world.Loader
+	chunkRadius    atomic.Int32
+	maxChunkRadius int32
+	// revealQueue holds positions of blocks that exposed the blocks around them, which are revealed to the
+	// client if they were hidden. revealed holds the hidden blocks already revealed to the client, per chunk,
+	// until the chunk is sent again or unloaded. lastRevealPos is the position of the player when blocks
+	// close to it were last revealed.
+	revealMu      sync.Mutex
+	revealQueue   []cube.Pos
+	revealed      map[world.ChunkPos]map[cube.Pos]struct{}
+	lastRevealPos cube.Pos
+	// chunksPerTick is the maximum amount of chunks sent to the client every tick.
+	chunksPerTick atomic.Int64
 
 	teleportPos atomic.Value[*mgl64.Vec3]
 
@@ -57,6 +95,10 @@ type Session struct {
 	entityRuntimeIDs map[world.Entity]uint64
 	entities         map[uint64]world.Entity
 	hiddenEntities   map[world.Entity]struct{}
+	// outOfRange holds the entities that are hidden from the client because they are further away than the
+	// entityRange. If entityRange is 0, entities are shown as long as their chunk is loaded.
+	outOfRange  map[world.Entity]struct{}
+	entityRange atomic.Float64
 
 	// heldSlot is the slot in the inventory that the controllable is holding.
 	heldSlot                     *atomic.Uint32
@@ -66,11 +108,14 @@ type Session struct {
 	breakingPos cube.Pos
 
 	inTransaction, containerOpened atomic.Bool
-	openedWindowID                 atomic.Uint32
-	openedContainerID              atomic.Uint32
-	openedWindow                   atomic.Value[*inventory.Inventory]
-	openedPos                      atomic.Value[cube.Pos]
-	swingingArm                    atomic.Bool
+	// virtualContainer is true if the container opened is not backed by a block in the world, but by a chest
+	// that was only sent to the client using OpenInventory.
+	virtualContainer  atomic.Bool
+	openedWindowID    atomic.Uint32
+	openedContainerID atomic.Uint32
+	openedWindow      atomic.Value[*inventory.Inventory]
+	openedPos         atomic.Value[cube.Pos]
+	swingingArm       atomic.Bool
 
 	recipes map[uint32]recipe.Recipe
 
@@ -80,6 +125,13 @@ type Session struct {
 	invOpened             bool
 
 	joinMessage, quitMessage string
+	chat                     *chatLimiter
+	sink                     event.Sink
+
+	// packets is the PacketPolicy of the Session. violations holds the number of invalid packets received
+	// from the client so far. It is only accessed while handling packets.
+	packets    PacketPolicy
+	violations int
 
 	closeBackground chan struct{}
 }
@@ -135,59 +187,21 @@ const selfEntityRuntimeID = 1
 // must therefore always be 1.
 var errSelfRuntimeID = errors.New("invalid entity runtime ID: runtime ID for self must always be 1")
 
-// New returns a new session using a controllable entity. The session will control this entity using the
-// packets that it receives.
-// New takes the connection from which to accept packets. It will start handling these packets after a call to
-// Session.Spawn().
-func New(conn Conn, maxChunkRadius int, log Logger, joinMessage, quitMessage string) *Session {
-	r := conn.ChunkRadius()
-	if r > maxChunkRadius {
-		r = maxChunkRadius
-		_ = conn.WritePacket(&packet.ChunkRadiusUpdated{ChunkRadius: int32(r)})
-	}
-
-	s := &Session{}
-	*s = Session{
-		openChunkTransactions:  make([]map[uint64]struct{}, 0, 8),
-		closeBackground:        make(chan struct{}),
-		ui:                     inventory.New(53, s.handleInterfaceUpdate),
-		handlers:               map[uint32]packetHandler{},
-		entityRuntimeIDs:       map[world.Entity]uint64{},
-		entities:               map[uint64]world.Entity{},
-		hiddenEntities:         map[world.Entity]struct{}{},
-		blobs:                  map[uint64][]byte{},
-		chunkRadius:            int32(r),
-		maxChunkRadius:         int32(maxChunkRadius),
-		conn:                   conn,
-		log:                    log,
-		currentEntityRuntimeID: 1,
-		heldSlot:               atomic.NewUint32(0),
-		joinMessage:            joinMessage,
-		quitMessage:            quitMessage,
-		openedWindow:           *atomic.NewValue(inventory.New(1, nil)),
-	}
-
-	s.registerHandlers()
-	return s
-}
-
 // Spawn makes the Controllable passed spawn in the world.World.
 // The function passed will be called when the session stops running.
-func (s *Session) Spawn(c Controllable, pos mgl64.Vec3, w *world.World, gm world.GameMode, onStop func(controllable Controllable)) {
+func (s *Session) Spawn(c Controllable, pos mgl64.Vec3, w *world.World, gm world.GameMode, onStop func(controllable Controllable, reason DisconnectReason)) {
 	s.onStop = onStop
 	s.c = c
 	s.recipes = make(map[uint32]recipe.Recipe)
 	s.entityRuntimeIDs[c] = selfEntityRuntimeID
 	s.entities[selfEntityRuntimeID] = c
 
-	s.chunkLoader = world.NewLoader(int(s.chunkRadius), w, s)
+	s.chunkLoader = world.NewLoader(int(s.chunkRadius.Load()), w, s)
 	s.chunkLoader.Move(pos)
-	s.writePacket(&packet.NetworkChunkPublisherUpdate{
-		Position: protocol.BlockPos{int32(pos[0]), int32(pos[1]), int32(pos[2])},
-		Radius:   uint32(s.chunkRadius) << 4,
-	})
+	s.sendPublisherUpdate(pos)
 
 	s.sendAvailableEntities(w)
+	s.ViewGamerules(w.Gamerules())
 
 	s.initPlayerList()
 
@@ -200,7 +214,7 @@ func (s *Session) Spawn(c Controllable, pos mgl64.Vec3, w *world.World, gm world
 
 	chat.Global.Subscribe(c)
 	if s.joinMessage != "" {
-		_, _ = fmt.Fprintln(chat.Global, text.Colourf("<yellow>%v</yellow>", fmt.Sprintf(s.joinMessage, s.conn.IdentityData().DisplayName)))
+		_, _ = fmt.Fprintln(chat.Global, text.Colourf("<yellow>%v</yellow>", s.formatMessage(s.joinMessage)))
 	}
 
 	s.sendInv(s.inv, protocol.WindowIDInventory)
@@ -242,7 +256,8 @@ func (s *Session) close() {
 		}
 	}
 
-	s.onStop(s.c)
+	reason, _ := s.DisconnectReason()
+	s.onStop(s.c, reason)
 
 	// Clear the inventories so that they no longer hold references to the connection.
 	_ = s.inv.Close()
@@ -260,7 +275,7 @@ func (s *Session) close() {
 	s.entityMutex.Unlock()
 
 	if s.quitMessage != "" {
-		_, _ = fmt.Fprintln(chat.Global, text.Colourf("<yellow>%v</yellow>", fmt.Sprintf(s.quitMessage, s.conn.IdentityData().DisplayName)))
+		_, _ = fmt.Fprintln(chat.Global, text.Colourf("<yellow>%v</yellow>", s.formatMessage(s.quitMessage)))
 	}
 	chat.Global.Unsubscribe(s.c)
 }
@@ -298,21 +313,33 @@ func (s *Session) handlePackets() {
 		// If this function ends up panicking, we don't want to call s.Close() as it may cause the entire
 		// server to freeze without printing the actual panic message.
 		// Instead, we check if there is a panic to recover, and just propagate the panic if this does happen
-		// to be the case.
+		// to be the case, unless the Session was created with a function to handle panics.
 		if err := recover(); err != nil {
-			panic(err)
+			if s.panicFunc == nil {
+				panic(err)
+			}
+			s.handlePanic(err)
 		}
 		_ = s.Close()
 	}()
+	s.lastPacket.Store(time.Now().UnixNano())
 	for {
 		pk, err := s.conn.ReadPacket()
 		if err != nil {
+			// The connection is closed if no packets are received for a few seconds. Active clients send packets
+			// every tick, so a long period without packets means the connection timed out.
+			if s.sinceLastPacket() >= time.Second*5 {
+				s.setDisconnectReason(DisconnectReasonTimeout)
+			} else {
+				s.setDisconnectReason(DisconnectReasonQuit)
+			}
 			return
 		}
-		if err := s.handlePacket(pk); err != nil {
-			// An error occurred during the handling of a packet. Print the error and stop handling any more
-			// packets.
-			s.log.Debugf("failed processing packet from %v (%v): %v\n", s.conn.RemoteAddr(), s.c.Name(), err)
+		s.lastPacket.Store(time.Now().UnixNano())
+		if err := s.handlePacket(pk); err != nil && s.violation(err) {
+			// An error occurred during the handling of a packet and the client sent too many invalid packets.
+			// Stop handling any more packets.
+			s.setDisconnectReason(DisconnectReasonKick)
 			return
 		}
 	}
@@ -329,13 +356,31 @@ func (s *Session) background() {
 		i                 int
 	)
 	defer t.Stop()
+	defer func() {
+		if s.panicFunc == nil {
+			return
+		}
+		if err := recover(); err != nil {
+			s.handlePanic(err)
+			// Closing the connection stops handlePackets, which then closes the Session.
+			_ = s.conn.Close()
+		}
+	}()
 
 	for {
 		select {
 		case <-t.C:
 			s.sendChunks()
+			s.revealBlocks()
+			if !s.flushImmediately {
+				s.flush()
+			}
+			if i%10 == 0 {
+				s.updateEntityRange()
+			}
 
 			if i++; i%20 == 0 {
+				s.checkTimeout()
 				// Enum resending happens relatively often and frequent updates are more important than with full
 				// command changes. Those are generally only related to permission changes, which doesn't happen often.
 				s.resendEnums(enums, enumValues)
@@ -352,15 +397,49 @@ func (s *Session) background() {
 	}
 }
 
+// handlePanic logs the panic value passed with the stack trace of the goroutine that panicked and calls the
+// panicFunc of the Session.
+func (s *Session) handlePanic(v any) {
+	stack := debug.Stack()
+	s.log.Errorf("recovered panic in session of %v (%v): %v\n%s", s.conn.RemoteAddr(), s.c.Name(), v, stack)
+	s.panicFunc(v, stack)
+}
+
+// sinceLastPacket returns the duration that has passed since the last packet was received from the client.
+func (s *Session) sinceLastPacket() time.Duration {
+	return time.Since(time.Unix(0, s.lastPacket.Load()))
+}
+
+// checkTimeout closes the connection of the Session if no packets were received from the client for longer than
+// the read timeout of the Session. This closes half-open connections that would otherwise only be closed once
+// the underlying connection times out.
+func (s *Session) checkTimeout() {
+	if s.readTimeout <= 0 {
+		return
+	}
+	if since := s.sinceLastPacket(); since > s.readTimeout {
+		s.log.Debugf("connection %v (%v) timed out: no packets received for %v\n", s.conn.RemoteAddr(), s.c.Name(), since.Round(time.Millisecond))
+		s.setDisconnectReason(DisconnectReasonTimeout)
+		_ = s.conn.Close()
+	}
+}
+
+// sendPublisherUpdate sends the position around which the client should keep chunks loaded. The client is
+// told to keep chunks up to two chunks beyond the chunk radius, so that chunks that the world.Loader remembers
+// to have sent, and therefore does not send again, are never discarded by the client.
+func (s *Session) sendPublisherUpdate(pos mgl64.Vec3) {
+	s.writePacket(&packet.NetworkChunkPublisherUpdate{
+		Position: protocol.BlockPos{int32(pos[0]), int32(pos[1]), int32(pos[2])},
+		Radius:   uint32(s.chunkRadius.Load()+2) << 4,
+	})
+}
+
 // sendChunks sends the next up to 4 chunks to the connection. What chunks are loaded depends on the connection of
 // the chunk loader and the chunks that were previously loaded.
 func (s *Session) sendChunks() {
 	pos := s.c.Position()
 	s.chunkLoader.Move(pos)
-	s.writePacket(&packet.NetworkChunkPublisherUpdate{
-		Position: protocol.BlockPos{int32(pos[0]), int32(pos[1]), int32(pos[2])},
-		Radius:   uint32(s.chunkRadius) << 4,
-	})
+	s.sendPublisherUpdate(pos)
 
 	const maxChunkTransactions = 8
 
@@ -371,12 +450,29 @@ func (s *Session) sendChunks() {
 	s.blobMu.Lock()
 	toLoad := maxChunkTransactions - len(s.openChunkTransactions)
 	s.blobMu.Unlock()
-	if toLoad > 4 {
-		toLoad = 4
+	if n := int(s.chunksPerTick.Load()); toLoad > n {
+		toLoad = n
 	}
 	s.chunkLoader.Load(toLoad)
 }
 
+// ChunksPerTick returns the maximum amount of chunks sent to the client every tick.
+func (s *Session) ChunksPerTick() int {
+	return int(s.chunksPerTick.Load())
+}
+
+// SetChunksPerTick sets the maximum amount of chunks sent to the client every tick. Values lower than 1 are
+// changed to 1.
+func (s *Session) SetChunksPerTick(n int) {
+	if s == Nop {
+		return
+	}
+	if n < 1 {
+		n = 1
+	}
+	s.chunksPerTick.Store(int64(n))
+}
+
 // handleWorldSwitch handles the player of the Session switching worlds.
 func (s *Session) handleWorldSwitch(w *world.World) {
 	if s.conn.ClientCacheEnabled() {
@@ -398,7 +494,35 @@ func (s *Session) handleWorldSwitch(w *world.World) {
 		s.changeDimension(dim, false)
 	}
 	s.ViewEntityTeleport(s.c, s.c.Position())
+	s.ViewGamerules(w.Gamerules())
 	s.chunkLoader.ChangeWorld(w)
+
+	// Changing the world of the chunk loader hides all entities in the chunks that were loaded. Entities that
+	// were shown some other way, or that left those chunks without being hidden, are hidden here so that the
+	// view of the new world starts out clean.
+	s.entityMutex.RLock()
+	stale := make([]world.Entity, 0, len(s.entityRuntimeIDs))
+	for e := range s.entityRuntimeIDs {
+		if e != s.c && e.World() != w {
+			stale = append(stale, e)
+		}
+	}
+	s.entityMutex.RUnlock()
+	for _, e := range stale {
+		s.HideEntity(e)
+	}
+}
+
+// record writes a Record of the type passed, caused by the player of the Session, to the event.Sink of the
+// Session.
+func (s *Session) record(t event.RecordType, message string) {
+	s.sink.Write(event.Record{
+		Time:    time.Now(),
+		Type:    t,
+		Name:    s.conn.IdentityData().DisplayName,
+		XUID:    s.conn.IdentityData().XUID,
+		Message: message,
+	})
 }
 
 // changeDimension changes the dimension of the client. If silent is set to true, the portal noise will be stopped
@@ -419,8 +543,15 @@ func (s *Session) changeDimension(dim int32, silent bool) {
 // handlePacket handles an incoming packet, processing it accordingly. If the packet had invalid data or was
 // otherwise not valid in its context, an error is returned.
 func (s *Session) handlePacket(pk packet.Packet) error {
+	if f := s.onPacket.Load(); f != nil && f(pk) {
+		// The packet was handled by the OnPacket function, so we don't handle it ourselves.
+		return nil
+	}
 	handler, ok := s.handlers[pk.ID()]
 	if !ok {
+		if s.packets.Strict {
+			return fmt.Errorf("%T: %w", pk, errUnexpectedPacket)
+		}
 		s.log.Debugf("unhandled packet %T%v from %v\n", pk, fmt.Sprintf("%+v", pk)[1:], s.conn.RemoteAddr())
 		return nil
 	}
@@ -437,38 +568,39 @@ func (s *Session) handlePacket(pk packet.Packet) error {
 // registerHandlers registers all packet handlers found in the packetHandler package.
 func (s *Session) registerHandlers() {
 	s.handlers = map[uint32]packetHandler{
-		packet.IDActorEvent:            nil,
-		packet.IDAdventureSettings:     nil, // Deprecated, the client still sends this though.
-		packet.IDAnimate:               nil,
-		packet.IDAnvilDamage:           nil,
-		packet.IDBlockActorData:        &BlockActorDataHandler{},
-		packet.IDBlockPickRequest:      &BlockPickRequestHandler{},
-		packet.IDBookEdit:              &BookEditHandler{},
-		packet.IDBossEvent:             nil,
-		packet.IDClientCacheBlobStatus: &ClientCacheBlobStatusHandler{},
-		packet.IDCommandRequest:        &CommandRequestHandler{},
-		packet.IDContainerClose:        &ContainerCloseHandler{},
-		packet.IDCraftingEvent:         nil,
-		packet.IDEmote:                 &EmoteHandler{},
-		packet.IDEmoteList:             nil,
-		packet.IDFilterText:            nil,
-		packet.IDInteract:              &InteractHandler{},
-		packet.IDInventoryTransaction:  &InventoryTransactionHandler{},
-		packet.IDItemFrameDropItem:     nil,
-		packet.IDItemStackRequest:      &ItemStackRequestHandler{changes: map[byte]map[byte]changeInfo{}, responseChanges: map[int32]map[*inventory.Inventory]map[byte]responseChange{}},
-		packet.IDLevelSoundEvent:       &LevelSoundEventHandler{},
-		packet.IDMobEquipment:          &MobEquipmentHandler{},
-		packet.IDModalFormResponse:     &ModalFormResponseHandler{forms: make(map[uint32]form.Form)},
-		packet.IDMovePlayer:            nil,
-		packet.IDPlayerAction:          &PlayerActionHandler{},
-		packet.IDPlayerAuthInput:       &PlayerAuthInputHandler{},
-		packet.IDPlayerSkin:            &PlayerSkinHandler{},
-		packet.IDRequestAbility:        &RequestAbilityHandler{},
-		packet.IDRequestChunkRadius:    &RequestChunkRadiusHandler{},
-		packet.IDRespawn:               &RespawnHandler{},
-		packet.IDSubChunkRequest:       &SubChunkRequestHandler{},
-		packet.IDText:                  &TextHandler{},
-		packet.IDTickSync:              nil,
+		packet.IDActorEvent:                  nil,
+		packet.IDAdventureSettings:           nil, // Deprecated, the client still sends this though.
+		packet.IDAnimate:                     nil,
+		packet.IDAnvilDamage:                 nil,
+		packet.IDBlockActorData:              &BlockActorDataHandler{},
+		packet.IDBlockPickRequest:            &BlockPickRequestHandler{},
+		packet.IDBookEdit:                    &BookEditHandler{},
+		packet.IDBossEvent:                   nil,
+		packet.IDClientCacheBlobStatus:       &ClientCacheBlobStatusHandler{},
+		packet.IDCommandRequest:              &CommandRequestHandler{},
+		packet.IDContainerClose:              &ContainerCloseHandler{},
+		packet.IDCraftingEvent:               nil,
+		packet.IDEmote:                       &EmoteHandler{},
+		packet.IDEmoteList:                   nil,
+		packet.IDFilterText:                  nil,
+		packet.IDInteract:                    &InteractHandler{},
+		packet.IDInventoryTransaction:        &InventoryTransactionHandler{},
+		packet.IDItemFrameDropItem:           nil,
+		packet.IDItemStackRequest:            &ItemStackRequestHandler{changes: map[byte]map[byte]changeInfo{}, responseChanges: map[int32]map[*inventory.Inventory]map[byte]responseChange{}},
+		packet.IDLevelSoundEvent:             &LevelSoundEventHandler{},
+		packet.IDMobEquipment:                &MobEquipmentHandler{},
+		packet.IDModalFormResponse:           &ModalFormResponseHandler{forms: make(map[uint32]form.Form)},
+		packet.IDMovePlayer:                  nil,
+		packet.IDPlayerAction:                &PlayerActionHandler{},
+		packet.IDPlayerAuthInput:             &PlayerAuthInputHandler{},
+		packet.IDPlayerSkin:                  &PlayerSkinHandler{},
+		packet.IDRequestAbility:              &RequestAbilityHandler{},
+		packet.IDRequestChunkRadius:          &RequestChunkRadiusHandler{},
+		packet.IDRespawn:                     &RespawnHandler{},
+		packet.IDSetLocalPlayerAsInitialised: &SetLocalPlayerAsInitialisedHandler{},
+		packet.IDSubChunkRequest:             &SubChunkRequestHandler{},
+		packet.IDText:                        &TextHandler{},
+		packet.IDTickSync:                    nil,
 	}
 }
 
@@ -484,12 +616,99 @@ func (s *Session) handleInterfaceUpdate(slot int, _, item item.Stack) {
 	}
 }
 
+// OnSpawn adds a function that is called once the client has received the chunks around its spawn position
+// and signalled that it has spawned in the world. If the client has already spawned, f is called immediately.
+func (s *Session) OnSpawn(f func()) {
+	s.spawnMu.Lock()
+	if s == Nop || s.spawned {
+		s.spawnMu.Unlock()
+		f()
+		return
+	}
+	s.onSpawn = append(s.onSpawn, f)
+	s.spawnMu.Unlock()
+}
+
+// markSpawned marks the Session as spawned and calls all functions added using OnSpawn. It does nothing if the
+// Session was already spawned.
+func (s *Session) markSpawned() {
+	s.spawnMu.Lock()
+	if s.spawned {
+		s.spawnMu.Unlock()
+		return
+	}
+	funcs := s.onSpawn
+	s.spawned, s.onSpawn = true, nil
+	s.spawnMu.Unlock()
+
+	for _, f := range funcs {
+		f()
+	}
+}
+
+// WritePacket writes a packet.Packet directly to the connection of the Session. WritePacket should only be
+// used to send packets that are not otherwise supported by dragonfly, as sending packets that conflict with
+// the state held by the server may lead to unexpected behaviour on the client side.
+func (s *Session) WritePacket(pk packet.Packet) {
+	s.writePacket(pk)
+}
+
+// OnPacket sets a function that is called for every packet read from the connection of the Session, before
+// it is handled. If f returns true, the Session does not handle the packet itself. Passing nil removes a
+// function previously set. f is called on the goroutine that reads packets, so it should not block.
+func (s *Session) OnPacket(f func(pk packet.Packet) bool) {
+	if s == Nop {
+		return
+	}
+	s.onPacket.Store(f)
+}
+
+// Conn returns the underlying Conn of the Session. Packets read from the Conn directly are not handled by
+// the Session, so OnPacket should be used to inspect packets sent by the client instead.
+func (s *Session) Conn() Conn {
+	return s.conn
+}
+
+// formatMessage formats a join or quit message of the Session. The {name} placeholder and a '%v' verb are
+// replaced with the name of the player and the {online} placeholder with the number of players online.
+func (s *Session) formatMessage(msg string) string {
+	sessionMu.Lock()
+	online := len(sessions)
+	sessionMu.Unlock()
+
+	name := s.conn.IdentityData().DisplayName
+	msg = strings.NewReplacer("{name}", name, "{online}", strconv.Itoa(online)).Replace(msg)
+	if strings.Contains(msg, "%v") {
+		return fmt.Sprintf(msg, name)
+	}
+	return msg
+}
+
 // writePacket writes a packet to the session's connection if it is not Nop.
 func (s *Session) writePacket(pk packet.Packet) {
 	if s == Nop {
 		return
 	}
 	_ = s.conn.WritePacket(pk)
+	if s.flushImmediately {
+		s.flush()
+	}
+}
+
+// flush flushes all packets buffered by the connection of the Session. Packets are flushed in the same order
+// as they were written.
+func (s *Session) flush() {
+	_ = s.conn.Flush()
+	s.flushes.Inc()
+}
+
+// Flushes returns the number of times the connection of the Session has been flushed. It may be used to tune
+// the FlushRate and FlushImmediately options of the server.
+func (s *Session) Flushes() uint64 {
+	if s == Nop {
+		return 0
+	}
+	return s.flushes.Load()
 }
 
 // initPlayerList initialises the player list of the session and sends the session itself to all other
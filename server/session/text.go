@@ -15,6 +15,16 @@ func (s *Session) SendMessage(message string) {
 	})
 }
 
+// SendTranslation sends a message translated by the client using the translation key and parameters passed.
+func (s *Session) SendTranslation(key string, parameters ...string) {
+	s.writePacket(&packet.Text{
+		TextType:         packet.TextTypeTranslation,
+		NeedsTranslation: true,
+		Message:          key,
+		Parameters:       parameters,
+	})
+}
+
 // SendTip ...
 func (s *Session) SendTip(message string) {
 	s.writePacket(&packet.Text{
@@ -87,23 +97,57 @@ func (s *Session) SendScoreboard(sb *scoreboard.Scoreboard) {
 		}
 	}
 	pk := &packet.SetScore{ActionType: packet.ScoreboardActionModify}
-	for k, line := range sb.Lines() {
-		if len(line) == 0 {
-			line = "§" + colours[k]
-		}
-		pk.Entries = append(pk.Entries, protocol.ScoreboardEntry{
-			EntryID:       int64(k),
-			ObjectiveName: sb.Name(),
-			Score:         int32(k),
-			IdentityType:  protocol.ScoreboardIdentityFakePlayer,
-			DisplayName:   line,
-		})
+	for k, e := range sb.Entries() {
+		pk.Entries = append(pk.Entries, scoreboardEntry(sb.Name(), k, e))
 	}
 	if len(pk.Entries) > 0 {
 		s.writePacket(pk)
 	}
 }
 
+// UpdateScoreboardEntry changes a single line of the scoreboard currently shown, without sending the rest of the
+// scoreboard again.
+func (s *Session) UpdateScoreboardEntry(index int, e scoreboard.Entry) {
+	if s == Nop || index < 0 || index >= len(colours) {
+		return
+	}
+	name := s.currentScoreboard.Load()
+	if name == "" {
+		return
+	}
+	s.writePacket(&packet.SetScore{ActionType: packet.ScoreboardActionRemove, Entries: []protocol.ScoreboardEntry{{
+		EntryID:       int64(index),
+		ObjectiveName: name,
+	}}})
+	s.writePacket(&packet.SetScore{ActionType: packet.ScoreboardActionModify, Entries: []protocol.ScoreboardEntry{
+		scoreboardEntry(name, index, e),
+	}})
+
+	lines := append([]string(nil), s.currentLines.Load()...)
+	if index >= len(lines) {
+		lines = append(lines, make([]string, index-len(lines)+1)...)
+	}
+	lines[index] = e.Text
+	s.currentLines.Store(lines)
+}
+
+// scoreboardEntry returns the protocol.ScoreboardEntry of the line with the index passed in the scoreboard with
+// the name passed.
+func scoreboardEntry(name string, index int, e scoreboard.Entry) protocol.ScoreboardEntry {
+	line := e.Text
+	if len(line) == 0 {
+		// Lines with equal text are merged by the client, so empty lines are made unique using colour codes.
+		line = "§" + colours[index]
+	}
+	return protocol.ScoreboardEntry{
+		EntryID:       int64(index),
+		ObjectiveName: name,
+		Score:         int32(e.Score),
+		IdentityType:  protocol.ScoreboardIdentityFakePlayer,
+		DisplayName:   line,
+	}
+}
+
 // colours holds a list of colour codes to be filled out for empty lines in a scoreboard.
 var colours = [15]string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "a", "b", "c", "d", "e", "f"}
 
@@ -38,14 +38,81 @@ type OffsetEntity interface {
 	NetworkOffset() float64
 }
 
-// entityHidden checks if a world.Entity is being explicitly hidden from the Session.
+// entityHidden checks if a world.Entity is being explicitly hidden from the Session or is hidden because it is
+// out of the entity tracking range of the Session.
 func (s *Session) entityHidden(e world.Entity) bool {
 	s.entityMutex.RLock()
 	_, ok := s.hiddenEntities[e]
+	_, outOfRange := s.outOfRange[e]
 	s.entityMutex.RUnlock()
+	if outOfRange {
+		return true
+	}
+	if c, controllable := e.(Controllable); controllable && !ok && !c.GameMode().Visible() {
+		// Players with a game mode that isn't visible, such as spectator mode, are hidden from everyone but
+		// themselves.
+		return s.entityRuntimeID(e) != selfEntityRuntimeID
+	}
 	return ok
 }
 
+// SetEntityTrackingRange sets the distance in blocks beyond which entities are removed from the client, even if
+// the chunk they are in is loaded. They are shown again once they are within the range. Passing 0 shows all
+// entities in loaded chunks, which is the default.
+func (s *Session) SetEntityTrackingRange(r float64) {
+	s.entityRange.Store(r)
+}
+
+// updateEntityRange hides the entities shown to the Session that are further away than the entity tracking range
+// and shows entities previously hidden that are within the range again.
+func (s *Session) updateEntityRange() {
+	r := s.entityRange.Load()
+	if r <= 0 || s.c == nil {
+		return
+	}
+	w, pos := s.c.World(), s.c.Position()
+	inRange := func(e world.Entity) bool {
+		return e.Position().Sub(pos).Len() <= r
+	}
+
+	s.entityMutex.RLock()
+	shown := make([]world.Entity, 0, len(s.entities))
+	for _, e := range s.entities {
+		_, hidden := s.hiddenEntities[e]
+		_, outOfRange := s.outOfRange[e]
+		if !hidden && !outOfRange && e != s.c {
+			shown = append(shown, e)
+		}
+	}
+	hidden := make([]world.Entity, 0, len(s.outOfRange))
+	for e := range s.outOfRange {
+		hidden = append(hidden, e)
+	}
+	s.entityMutex.RUnlock()
+
+	for _, e := range shown {
+		if !inRange(e) {
+			s.HideEntity(e)
+			s.entityMutex.Lock()
+			s.outOfRange[e] = struct{}{}
+			s.entityMutex.Unlock()
+		}
+	}
+	for _, e := range hidden {
+		if e.World() != w || inRange(e) {
+			s.entityMutex.Lock()
+			delete(s.outOfRange, e)
+			s.entityMutex.Unlock()
+		}
+		if e.World() == w && inRange(e) {
+			s.ViewEntity(e)
+			s.ViewEntityState(e)
+			s.ViewEntityItems(e)
+			s.ViewEntityArmour(e)
+		}
+	}
+}
+
 // ViewEntity ...
 func (s *Session) ViewEntity(e world.Entity) {
 	if s.entityRuntimeID(e) == selfEntityRuntimeID {
@@ -460,6 +527,13 @@ func (s *Session) ViewParticle(pos mgl64.Vec3, p world.Particle) {
 			EventType: packet.LevelEventParticleLegacyEvent | 10,
 			Position:  vec64To32(pos),
 		})
+	case particle.Custom:
+		s.writePacket(&packet.SpawnParticleEffect{
+			Dimension:      byte(s.c.World().Dimension().EncodeDimension()),
+			EntityUniqueID: -1,
+			Position:       vec64To32(pos),
+			ParticleName:   pa.Name,
+		})
 	}
 }
 
@@ -755,6 +829,21 @@ func (s *Session) playSound(pos mgl64.Vec3, t world.Sound, disableRelative bool)
 		pk.SoundType = packet.SoundEventComposterFillLayer
 	case sound.ComposterReady:
 		pk.SoundType = packet.SoundEventComposterReady
+	case sound.Custom:
+		volume, pitch := so.Volume, so.Pitch
+		if volume == 0 {
+			volume = 1
+		}
+		if pitch == 0 {
+			pitch = 1
+		}
+		s.writePacket(&packet.PlaySound{
+			SoundName: so.Name,
+			Position:  vec64To32(pos),
+			Volume:    float32(volume),
+			Pitch:     float32(pitch),
+		})
+		return
 	}
 	s.writePacket(pk)
 }
@@ -768,6 +857,21 @@ func (s *Session) PlaySound(t world.Sound) {
 	s.playSound(entity.EyePosition(s.c), t, true)
 }
 
+// PlaySoundAt plays a world.Sound to the client at the position passed, so that its volume depends on the
+// distance of the player to the position.
+func (s *Session) PlaySoundAt(pos mgl64.Vec3, t world.Sound) {
+	if s == Nop {
+		return
+	}
+	s.playSound(pos, t, false)
+}
+
+// StopSound stops a sound played to the client by its name, such as a looping sound or music played using
+// sound.Custom. If name is empty, all sounds currently playing are stopped.
+func (s *Session) StopSound(name string) {
+	s.writePacket(&packet.StopSound{SoundName: name, StopAll: name == ""})
+}
+
 // ViewSound ...
 func (s *Session) ViewSound(pos mgl64.Vec3, soundType world.Sound) {
 	s.playSound(pos, soundType, false)
@@ -817,6 +921,9 @@ func (s *Session) ViewBlockUpdate(pos cube.Pos, b world.Block, layer int) {
 			NBTData:  NBTData,
 		})
 	}
+	if layer == 0 {
+		s.queueReveal(pos, b)
+	}
 }
 
 // ViewEntityAction ...
@@ -983,6 +1090,32 @@ func (s *Session) openNormalContainer(b block.Container, pos cube.Pos) {
 	s.sendInv(b.Inventory(), uint32(nextID))
 }
 
+// OpenInventory opens the inventory passed in a chest window. A chest is shown at the position passed to the
+// client only, which is replaced with the actual block in the world when the window is closed. The inventory
+// must have a size of 27. The Session views the inventory until the window is closed, so that changes to the
+// inventory are shown to the client.
+func (s *Session) OpenInventory(inv *inventory.Inventory, pos cube.Pos) {
+	s.closeCurrentContainer()
+
+	s.ViewBlockUpdate(pos, block.Chest{}, 0)
+
+	nextID := s.nextWindowID()
+	s.containerOpened.Store(true)
+	s.virtualContainer.Store(true)
+	s.openedWindow.Store(inv)
+	s.openedPos.Store(pos)
+	s.openedContainerID.Store(protocol.ContainerTypeContainer)
+	inv.AddViewer(s)
+
+	s.writePacket(&packet.ContainerOpen{
+		WindowID:                nextID,
+		ContainerType:           protocol.ContainerTypeContainer,
+		ContainerPosition:       protocol.BlockPos{int32(pos[0]), int32(pos[1]), int32(pos[2])},
+		ContainerEntityUniqueID: -1,
+	})
+	s.sendInv(inv, uint32(nextID))
+}
+
 // ViewSlotChange ...
 func (s *Session) ViewSlotChange(slot int, newItem item.Stack) {
 	if !s.containerOpened.Load() {
@@ -1085,6 +1218,20 @@ func (s *Session) ViewWeather(raining, thunder bool) {
 	s.writePacket(pk)
 }
 
+// ViewGamerules ...
+func (s *Session) ViewGamerules(rules map[string]any) {
+	gameRules := make([]protocol.GameRule, 0, len(rules))
+	for name, v := range rules {
+		gameRules = append(gameRules, protocol.GameRule{Name: name, Value: v})
+	}
+	s.sendGameRules(gameRules)
+}
+
+// ViewDifficulty ...
+func (s *Session) ViewDifficulty(d world.Difficulty) {
+	s.writePacket(&packet.SetDifficulty{Difficulty: uint32(difficultyID(d))})
+}
+
 // nextWindowID produces the next window ID for a new window. It is an int of 1-99.
 func (s *Session) nextWindowID() byte {
 	if s.openedWindowID.CAS(99, 1) {
@@ -1114,6 +1261,19 @@ func (s *Session) entityRuntimeID(e world.Entity) uint64 {
 	return id
 }
 
+// EntityRuntimeID returns the runtime ID that the client of the Session uses for the world.Entity passed. The
+// entity controlled by the Session always has the runtime ID 1, while other entities have a runtime ID unique
+// to the Session. False is returned if the entity is not currently known to the client.
+func (s *Session) EntityRuntimeID(e world.Entity) (uint64, bool) {
+	if s == Nop {
+		return 0, false
+	}
+	s.entityMutex.RLock()
+	defer s.entityMutex.RUnlock()
+	id, ok := s.entityRuntimeIDs[e]
+	return id, ok
+}
+
 // entityFromRuntimeID attempts to return an entity by its runtime ID. False is returned if no entity with the
 // ID could be found.
 func (s *Session) entityFromRuntimeID(id uint64) (world.Entity, bool) {
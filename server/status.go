@@ -4,19 +4,54 @@ import (
 	"github.com/sandertv/gophertunnel/minecraft"
 )
 
-// statusProvider handles the way the server shows up in the server list. The
-// online players and maximum players are not changeable from outside the
-// server, but the server name may be changed at any time.
+// ServerStatus is the status of a Server as shown in the server list of
+// players, returned by a function passed to Server.SetStatusProvider.
+type ServerStatus struct {
+	// MOTD is the name or message of the day of the Server shown in the
+	// server list. Minecraft colour codes may be used in the MOTD.
+	MOTD string
+	// PlayerCount is the amount of players shown to be online.
+	PlayerCount int
+	// MaxPlayers is the maximum amount of players shown. If set to 0, it is
+	// shown as PlayerCount + 1.
+	MaxPlayers int
+}
+
+// SetStatusProvider sets a function that is called to compute the status of
+// the Server shown in the server list, for example to show a maintenance
+// message or rotating MOTDs. The status shown to players is refreshed every
+// few seconds. Passing nil restores the default status, which shows the name
+// and player counts of the Server.
+// SetStatusProvider has no effect if a custom Config.StatusProvider was set.
+func (srv *Server) SetStatusProvider(f func() ServerStatus) {
+	srv.status.Store(f)
+}
+
+// statusProvider handles the way the server shows up in the server list. It
+// shows the current name of the Server, the amount of players online and the
+// maximum amount of players, all of which are updated live.
 type statusProvider struct {
-	name string
+	srv *Server
 }
 
 // ServerStatus returns the player count, max players and the server's name as
-// a minecraft.ServerStatus.
-func (s statusProvider) ServerStatus(playerCount, maxPlayers int) minecraft.ServerStatus {
+// a minecraft.ServerStatus, or the ServerStatus returned by the function set
+// using Server.SetStatusProvider.
+func (s statusProvider) ServerStatus(int, int) minecraft.ServerStatus {
+	if f := s.srv.status.Load(); f != nil {
+		status := f()
+		if status.MaxPlayers == 0 {
+			status.MaxPlayers = status.PlayerCount + 1
+		}
+		return minecraft.ServerStatus{
+			ServerName:  status.MOTD,
+			PlayerCount: status.PlayerCount,
+			MaxPlayers:  status.MaxPlayers,
+		}
+	}
 	return minecraft.ServerStatus{
-		ServerName:  s.name,
-		PlayerCount: playerCount,
-		MaxPlayers:  maxPlayers,
+		ServerName:  s.srv.Name(),
+		PlayerCount: s.srv.PlayerCount(),
+		MaxPlayers:  s.srv.MaxPlayerCount(),
 	}
 }
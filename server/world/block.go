@@ -49,16 +49,41 @@ type Liquid interface {
 	Harden(pos cube.Pos, w *World, flownIntoBy *cube.Pos) bool
 }
 
+// CustomBlock represents a block that is non-vanilla and requires a resource pack and extra steps to show it
+// to the client. Unlike vanilla blocks, the states of a CustomBlock do not need to be registered beforehand: They
+// are registered when the CustomBlock is passed to RegisterBlock.
+type CustomBlock interface {
+	Block
+	// Components returns the components of the block, such as 'minecraft:geometry' and
+	// 'minecraft:material_instances', that are sent to the client so that it knows how to render the block.
+	Components() map[string]any
+}
+
 // hashes holds a list of runtime IDs indexed by the hash of the Block that implements the blocks pointed to by those
 // runtime IDs. It is used to look up a block's runtime ID quickly.
 var hashes = intintmap.New(7000, 0.999)
 
+// customBlocks holds a list of all registered custom blocks, indexed by their name.
+var customBlocks = map[string]CustomBlock{}
+
 // RegisterBlock registers the Block passed. The EncodeBlock method will be used to encode and decode the
 // block passed. RegisterBlock panics if the block properties returned were not valid, existing properties.
 func RegisterBlock(b Block) {
 	name, properties := b.EncodeBlock()
 	h := stateHash{name: name, properties: hashProperties(properties)}
 
+	if c, ok := b.(CustomBlock); ok {
+		if blocksFinalised {
+			panic(fmt.Sprintf("cannot register custom block %v after the block registry was finalised", name))
+		}
+		if _, ok := stateRuntimeIDs[h]; !ok {
+			registerBlockState(blockState{Name: name, Properties: properties})
+		}
+		if _, ok := customBlocks[name]; !ok {
+			customBlocks[name] = c
+		}
+	}
+
 	rid, ok := stateRuntimeIDs[h]
 	if !ok {
 		// We assume all blocks must have all their states registered beforehand. Vanilla blocks will have
@@ -74,7 +99,11 @@ func RegisterBlock(b Block) {
 	}
 	blocks[rid] = b
 	hashes.Put(hash, int64(rid))
+	indexBlock(rid, b)
+}
 
+// indexBlock sets the values in the lookup tables indexed by runtime ID for the Block passed.
+func indexBlock(rid uint32, b Block) {
 	if diffuser, ok := b.(lightDiffuser); ok {
 		chunk.FilteringBlocks[rid] = diffuser.LightDiffusionLevel()
 	}
@@ -95,6 +124,11 @@ func RegisterBlock(b Block) {
 	}
 }
 
+// CustomBlocks returns a map of all registered custom blocks indexed by their name.
+func CustomBlocks() map[string]CustomBlock {
+	return customBlocks
+}
+
 // BlockRuntimeID attempts to return a runtime ID of a block previously registered using RegisterBlock().
 // If the runtime ID cannot be found because the Block wasn't registered, BlockRuntimeID will panic.
 func BlockRuntimeID(b Block) uint32 {
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	_ "embed"
 	"fmt"
+	"github.com/brentp/intintmap"
 	"github.com/df-mc/dragonfly/server/world/chunk"
 	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"hash/fnv"
 	"math"
 	"sort"
 	"strings"
@@ -34,6 +36,9 @@ var (
 	liquidDisplacingBlocks []bool
 	// airRID is the runtime ID of an air block.
 	airRID uint32
+	// blocksFinalised specifies if finaliseBlockRegistry was called. No more custom blocks may be registered
+	// after that.
+	blocksFinalised bool
 )
 
 func init() {
@@ -84,6 +89,58 @@ func registerBlockState(s blockState) {
 	chunk.LightBlocks = append(chunk.LightBlocks, 0)
 }
 
+// finaliseBlockRegistry is called after all blocks have been registered. If any custom blocks were registered, the
+// runtime IDs of all block states are reassigned so that they are ordered by the FNV-1 hash of their names, which
+// is the order in which the client assigns runtime IDs. Vanilla block states are already stored in this order, so
+// nothing changes if no custom blocks are registered.
+// Note that runtime IDs are never written to disk: Chunks are saved using the names and properties of blocks, so
+// reassigning runtime IDs does not affect worlds saved previously.
+//
+//lint:ignore U1000 Function is used through compiler directives.
+func finaliseBlockRegistry() {
+	if blocksFinalised {
+		return
+	}
+	blocksFinalised = true
+	if len(customBlocks) == 0 {
+		return
+	}
+
+	type entry struct {
+		b    Block
+		hash uint64
+	}
+	entries := make([]entry, len(blocks))
+	for i, b := range blocks {
+		name, _ := b.EncodeBlock()
+		h := fnv.New64()
+		_, _ = h.Write([]byte(name))
+		entries[i] = entry{b: b, hash: h.Sum64()}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].hash < entries[j].hash
+	})
+
+	stateRuntimeIDs = make(map[stateHash]uint32, len(entries))
+	hashes = intintmap.New(7000, 0.999)
+	for rid, e := range entries {
+		blocks[rid] = e.b
+		nbtBlocks[rid], randomTickBlocks[rid], liquidBlocks[rid], liquidDisplacingBlocks[rid] = false, false, false, false
+		chunk.FilteringBlocks[rid], chunk.LightBlocks[rid] = 15, 0
+
+		name, properties := e.b.EncodeBlock()
+		if name == "minecraft:air" {
+			airRID = uint32(rid)
+		}
+		stateRuntimeIDs[stateHash{name: name, properties: hashProperties(properties)}] = uint32(rid)
+		if _, ok := e.b.(unknownBlock); ok {
+			continue
+		}
+		hashes.Put(int64(e.b.Hash()), int64(rid))
+		indexBlock(uint32(rid), e.b)
+	}
+}
+
 // unknownBlock represents a block that has not yet been implemented. It is used for registering block
 // states that haven't yet been added.
 type unknownBlock struct {
@@ -0,0 +1,152 @@
+package world
+
+import (
+	"errors"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"github.com/google/uuid"
+	"golang.org/x/exp/maps"
+	"sync"
+)
+
+// Clone creates a new World named name that holds a copy of the current state of the World, so that changes
+// made to either World do not affect the other. Clone is useful for creating a fresh instance of a map for
+// every match of a minigame.
+// The chunks currently loaded, including their block entities, are copied into memory. Chunks that are not
+// loaded are read from the Provider of the World when they are first loaded by the clone, so the Provider
+// must not be closed while the clone is in use. Entities are not copied. The clone never writes to disk: Its
+// changes are kept in memory and discarded when it is closed.
+func (w *World) Clone(name string) (*World, error) {
+	if w == nil {
+		return nil, errors.New("clone world: world is nil")
+	}
+	select {
+	case <-w.closing:
+		return nil, errors.New("clone world: world is closed")
+	default:
+	}
+	p := &cloneProvider{
+		src:    w.provider(),
+		set:    w.set.clone(),
+		chunks: make(map[ChunkPos]chunk.SerialisedData),
+		nbt:    make(map[ChunkPos][]map[string]any),
+	}
+	p.set.Name = name
+
+	w.chunkMu.Lock()
+	chunks := maps.Clone(w.chunks)
+	w.chunkMu.Unlock()
+	for pos, c := range chunks {
+		c.Lock()
+		p.chunks[pos] = chunk.Encode(c.Chunk, chunk.DiskEncoding)
+		p.nbt[pos] = encodeBlockNBT(c.e)
+		c.Unlock()
+	}
+
+	conf := w.conf
+	conf.Provider, conf.ReadOnly, conf.SaveInterval, conf.RandSource = p, false, 0, nil
+	clone := conf.New()
+
+	w.regionMu.RLock()
+	clone.regions = maps.Clone(w.regions)
+	clone.spawnProtection, clone.bedrockProtection = w.spawnProtection, w.bedrockProtection
+	w.regionMu.RUnlock()
+	clone.SetVoidLevel(w.VoidLevel())
+	return clone, nil
+}
+
+// encodeBlockNBT encodes the block entities passed to NBT in the format used by Provider.SaveBlockNBT.
+func encodeBlockNBT(e map[cube.Pos]Block) []map[string]any {
+	m := make([]map[string]any, 0, len(e))
+	for pos, b := range e {
+		if n, ok := b.(NBTer); ok {
+			data := n.EncodeNBT()
+			data["x"], data["y"], data["z"] = int32(pos[0]), int32(pos[1]), int32(pos[2])
+			m = append(m, data)
+		}
+	}
+	return m
+}
+
+// clone returns a copy of the Settings that is not shared with any World.
+func (s *Settings) clone() *Settings {
+	s.Lock()
+	defer s.Unlock()
+	return &Settings{
+		Name:              s.Name,
+		Spawn:             s.Spawn,
+		Seed:              s.Seed,
+		Time:              s.Time,
+		TimeCycle:         s.TimeCycle,
+		RainTime:          s.RainTime,
+		Raining:           s.Raining,
+		ThunderTime:       s.ThunderTime,
+		Thundering:        s.Thundering,
+		WeatherCycle:      s.WeatherCycle,
+		CurrentTick:       s.CurrentTick,
+		DefaultGameMode:   s.DefaultGameMode,
+		Difficulty:        s.Difficulty,
+		TickRange:         s.TickRange,
+		FallDamage:        s.FallDamage,
+		KeepInventory:     s.KeepInventory,
+		ShowCoordinates:   s.ShowCoordinates,
+		TNTExplodes:       s.TNTExplodes,
+		PvP:               s.PvP,
+		ShowDeathMessages: s.ShowDeathMessages,
+	}
+}
+
+// cloneProvider is the Provider of a World created using World.Clone. It keeps chunks and block entities in
+// memory and reads chunks it does not have from the Provider of the original World, without ever writing to it.
+type cloneProvider struct {
+	src Provider
+	set *Settings
+
+	mu     sync.Mutex
+	chunks map[ChunkPos]chunk.SerialisedData
+	nbt    map[ChunkPos][]map[string]any
+}
+
+func (p *cloneProvider) Settings() *Settings    { return p.set }
+func (p *cloneProvider) SaveSettings(*Settings) {}
+func (p *cloneProvider) LoadChunk(pos ChunkPos, dim Dimension) (*chunk.Chunk, bool, error) {
+	p.mu.Lock()
+	data, ok := p.chunks[pos]
+	p.mu.Unlock()
+	if !ok {
+		return p.src.LoadChunk(pos, dim)
+	}
+	c, err := chunk.DiskDecode(data, dim.Range())
+	return c, true, err
+}
+func (p *cloneProvider) SaveChunk(pos ChunkPos, c *chunk.Chunk, _ Dimension) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.chunks[pos] = chunk.Encode(c, chunk.DiskEncoding)
+	return nil
+}
+func (p *cloneProvider) LoadBlockNBT(pos ChunkPos, dim Dimension) ([]map[string]any, error) {
+	p.mu.Lock()
+	_, ok := p.chunks[pos]
+	data := p.nbt[pos]
+	p.mu.Unlock()
+	if !ok {
+		return p.src.LoadBlockNBT(pos, dim)
+	}
+	return data, nil
+}
+func (p *cloneProvider) SaveBlockNBT(pos ChunkPos, data []map[string]any, _ Dimension) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.nbt[pos] = data
+	return nil
+}
+func (p *cloneProvider) LoadEntities(ChunkPos, Dimension, EntityRegistry) ([]Entity, error) {
+	return nil, nil
+}
+func (p *cloneProvider) SaveEntities(ChunkPos, []Entity, Dimension) error { return nil }
+func (p *cloneProvider) LoadPlayerSpawnPosition(uuid.UUID) (cube.Pos, bool, error) {
+	return cube.Pos{}, false, nil
+}
+func (p *cloneProvider) SavePlayerSpawnPosition(uuid.UUID, cube.Pos) error { return nil }
+func (p *cloneProvider) Close() error                                      { return nil }
@@ -28,10 +28,31 @@ type Config struct {
 	Generator Generator
 	// ReadOnly specifies if the World should be read-only, meaning no new data will be written to the Provider.
 	ReadOnly bool
+	// SaveInterval is the interval at which chunks modified in the World are saved to the Provider, so that
+	// changes are not lost if the process crashes. If set to 0, chunks are only saved when they are unloaded
+	// or when the World is closed.
+	SaveInterval time.Duration
+	// ChunkUnloadDelay is the duration that a chunk must be out of view of all viewers of the World before it
+	// is unloaded and saved. If set to 0, chunks are unloaded after 5 minutes.
+	ChunkUnloadDelay time.Duration
 	// RandomTickSpeed specifies the rate at which blocks should be ticked in the World. By default, each sub chunk has
 	// 3 blocks randomly ticked per sub chunk, so the default value is 3. Setting this value to -1 or lower will stop
 	// random ticking altogether, while setting it higher results in faster ticking.
 	RandomTickSpeed int
+	// EntityMergeThreshold is the number of entities in a single chunk above which entities that support it,
+	// such as dropped items, merge with identical entities anywhere in the chunk rather than only with those
+	// close by. If set to 0, entities only merge with those close by.
+	EntityMergeThreshold int
+	// MaxChunkEntities is the maximum number of entities that may be in a single chunk. Entities other than
+	// players and items added to a chunk that holds this many entities are not spawned, and a warning is
+	// logged. If set to 0, the number of entities in a chunk is not limited.
+	MaxChunkEntities int
+	// HiddenBlocks maps blocks, such as ores, that are hidden from viewers of the World to counter x-ray
+	// clients, to the block that they are replaced with. Blocks with the same name as one of the HiddenBlocks,
+	// regardless of their properties, are replaced in chunks sent to viewers implementing ObfuscatedViewer
+	// unless they are exposed to a transparent block. Viewers may reveal them again, for example when they
+	// come close. If HiddenBlocks is empty, no blocks are hidden.
+	HiddenBlocks map[Block]Block
 	// RandSource is the rand.Source used for generation of random numbers in a World, such as when selecting blocks to
 	// tick or when deciding where to strike lightning. If set to nil, `rand.NewSource(time.Now().Unix())` will be used
 	// to generate a new source.
@@ -45,6 +66,7 @@ type Config struct {
 // messages to this Logger when appropriate.
 type Logger interface {
 	Errorf(format string, a ...any)
+	Warnf(format string, a ...any)
 	Debugf(format string, a ...any)
 }
 
@@ -63,6 +85,9 @@ func (conf Config) New() *World {
 	if conf.Generator == nil {
 		conf.Generator = NopGenerator{}
 	}
+	if conf.ChunkUnloadDelay <= 0 {
+		conf.ChunkUnloadDelay = time.Minute * 5
+	}
 	if conf.RandomTickSpeed == 0 {
 		conf.RandomTickSpeed = 3
 	}
@@ -74,6 +99,7 @@ func (conf Config) New() *World {
 		scheduledUpdates: make(map[cube.Pos]int64),
 		entities:         make(map[Entity]ChunkPos),
 		viewers:          make(map[*Loader]Viewer),
+		regions:          make(map[string]Region),
 		chunks:           make(map[ChunkPos]*chunkData),
 		closing:          make(chan struct{}),
 		handler:          *atomic.NewValue[Handler](NopHandler{}),
@@ -82,10 +108,16 @@ func (conf Config) New() *World {
 		conf:             conf,
 		ra:               conf.Dim.Range(),
 		set:              s,
+		tps:              *atomic.NewFloat64(20),
 	}
+	w.voidLevel.Store(int64(w.ra[0]))
+	w.hidden = hiddenRuntimeIDs(conf.HiddenBlocks)
 	w.weather, w.ticker = weather{w: w}, ticker{w: w}
 
 	go w.tickLoop()
 	go w.chunkCacheJanitor()
+	if conf.SaveInterval > 0 {
+		go w.autoSave()
+	}
 	return w
 }
@@ -33,8 +33,23 @@ type (
 	nether    struct{}
 	end       struct{}
 	nopDim    struct{}
+	// overworldRange is an overworld with a custom building range.
+	overworldRange struct {
+		overworld
+		r cube.Range
+	}
 )
 
+// OverworldWithRange returns a Dimension that behaves like Overworld, but has the building range r instead of
+// [-64, 320). It may be used to create worlds with an extended height. The minimum of r and the maximum of r plus
+// one must be multiples of 16. Note that a World loaded from a Provider with a Dimension of a different Range
+// than it was saved with cannot be loaded correctly.
+func OverworldWithRange(r cube.Range) Dimension {
+	return overworldRange{r: r}
+}
+
+func (d overworldRange) Range() cube.Range { return d.r }
+
 func (overworld) Range() cube.Range                 { return cube.Range{-64, 319} }
 func (overworld) EncodeDimension() int              { return 0 }
 func (overworld) WaterEvaporates() bool             { return false }
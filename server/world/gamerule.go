@@ -0,0 +1,105 @@
+package world
+
+import "strings"
+
+// Gamerule returns the value of the gamerule with the name passed, such as 'doDaylightCycle' or 'keepInventory'.
+// Gamerule names are case-insensitive. If no gamerule with the name passed exists, Gamerule returns nil.
+// The gamerules currently supported are doDaylightCycle, doWeatherCycle, fallDamage, keepInventory,
+// pvp, showCoordinates, showDeathMessages and tntExplodes, all of which hold a bool value.
+func (w *World) Gamerule(name string) any {
+	if w == nil {
+		return nil
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	if v, ok := w.gamerule(strings.ToLower(name)); ok {
+		return *v
+	}
+	return nil
+}
+
+// SetGamerule sets the value of the gamerule with the name passed. Gamerule names are case-insensitive. The new
+// value is sent to all viewers of the World. SetGamerule does nothing if no gamerule with the name passed exists
+// or if the value passed is not of the type of the gamerule.
+func (w *World) SetGamerule(name string, value any) {
+	if w == nil {
+		return
+	}
+	name = strings.ToLower(name)
+	v, ok := value.(bool)
+	if !ok {
+		return
+	}
+	w.set.Lock()
+	rule, ok := w.gamerule(name)
+	if ok {
+		*rule = v
+	}
+	w.set.Unlock()
+	if !ok {
+		return
+	}
+
+	viewers, _ := w.allViewers()
+	for _, viewer := range viewers {
+		viewer.ViewGamerules(map[string]any{name: v})
+	}
+}
+
+// Gamerules returns a map of all gamerules of the World, indexed by their lowercase names.
+func (w *World) Gamerules() map[string]any {
+	if w == nil {
+		return nil
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	return map[string]any{
+		"dodaylightcycle":   w.set.TimeCycle,
+		"doweathercycle":    w.set.WeatherCycle,
+		"falldamage":        w.set.FallDamage,
+		"keepinventory":     w.set.KeepInventory,
+		"pvp":               w.set.PvP,
+		"showcoordinates":   w.set.ShowCoordinates,
+		"showdeathmessages": w.set.ShowDeathMessages,
+		"tntexplodes":       w.set.TNTExplodes,
+	}
+}
+
+// gamerule returns a pointer to the field in the Settings of the World that holds the value of the gamerule with
+// the lowercase name passed. gamerule must only be called while the Settings are locked.
+func (w *World) gamerule(name string) (*bool, bool) {
+	switch name {
+	case "dodaylightcycle":
+		return &w.set.TimeCycle, true
+	case "doweathercycle":
+		return &w.set.WeatherCycle, true
+	case "falldamage":
+		return &w.set.FallDamage, true
+	case "keepinventory":
+		return &w.set.KeepInventory, true
+	case "pvp":
+		return &w.set.PvP, true
+	case "showcoordinates":
+		return &w.set.ShowCoordinates, true
+	case "showdeathmessages":
+		return &w.set.ShowDeathMessages, true
+	case "tntexplodes":
+		return &w.set.TNTExplodes, true
+	}
+	return nil, false
+}
+
+// PvP checks if players in the World can damage other players.
+func (w *World) PvP() bool {
+	if w == nil {
+		return true
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	return w.set.PvP
+}
+
+// SetPvP sets if players in the World can damage other players. It is equivalent to setting the pvp gamerule.
+func (w *World) SetPvP(pvp bool) {
+	w.SetGamerule("pvp", pvp)
+}
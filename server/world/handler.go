@@ -35,6 +35,11 @@ type Handler interface {
 	// wood, that can be broken by fire. HandleBlockBurn is often succeeded by HandleFireSpread, when fire spreads to
 	// the position of the original block and the event.Context is not cancelled in HandleBlockBurn.
 	HandleBlockBurn(ctx *event.Context, pos cube.Pos)
+	// HandleExplosion handles an explosion at a position in the World. The entities and blocks affected by the
+	// explosion are passed and may be altered, for example to protect certain blocks. itemDropChance is the
+	// chance, between 0 and 1, that a destroyed block drops its items and spawnFire specifies if fire is spawned
+	// on the destroyed blocks. ctx.Cancel() may be called to cancel the explosion entirely.
+	HandleExplosion(ctx *event.Context, position mgl64.Vec3, entities *[]Entity, blocks *[]cube.Pos, itemDropChance *float64, spawnFire *bool)
 	// HandleEntitySpawn handles an entity being spawned into a World through a call to World.AddEntity.
 	HandleEntitySpawn(e Entity)
 	// HandleEntityDespawn handles an entity being despawned from a World through a call to World.RemoveEntity.
@@ -59,6 +64,8 @@ func (NopHandler) HandleLiquidHarden(*event.Context, cube.Pos, Block, Block, Blo
 func (NopHandler) HandleSound(*event.Context, Sound, mgl64.Vec3)                      {}
 func (NopHandler) HandleFireSpread(*event.Context, cube.Pos, cube.Pos)                {}
 func (NopHandler) HandleBlockBurn(*event.Context, cube.Pos)                           {}
-func (NopHandler) HandleEntitySpawn(Entity)                                           {}
-func (NopHandler) HandleEntityDespawn(Entity)                                         {}
-func (NopHandler) HandleClose()                                                       {}
+func (NopHandler) HandleExplosion(*event.Context, mgl64.Vec3, *[]Entity, *[]cube.Pos, *float64, *bool) {
+}
+func (NopHandler) HandleEntitySpawn(Entity)   {}
+func (NopHandler) HandleEntityDespawn(Entity) {}
+func (NopHandler) HandleClose()               {}
@@ -3,6 +3,7 @@ package world
 import (
 	"github.com/go-gl/mathgl/mgl64"
 	"math"
+	"sort"
 	"sync"
 )
 
@@ -18,16 +19,22 @@ type Loader struct {
 	pos       ChunkPos
 	loadQueue []ChunkPos
 	loaded    map[ChunkPos]*chunkData
+	// sent holds the versions of chunks most recently sent to the Viewer. Chunks that are loaded again without
+	// having been modified since are not sent to the Viewer again.
+	sent map[ChunkPos]uint64
 
 	closed bool
 }
 
 // NewLoader creates a new loader using the chunk radius passed. Chunks beyond this radius from the position
 // of the loader will never be loaded.
+// Chunks that are loaded again without having been modified since they were last sent are not sent to the
+// Viewer again, as long as they did not move further than one chunk beyond the radius. The Viewer must
+// therefore keep the chunks it viewed until they are at least two chunks beyond the radius.
 // The Viewer passed will handle the loading of chunks, including the viewing of entities that were loaded in
 // those chunks.
 func NewLoader(chunkRadius int, world *World, v Viewer) *Loader {
-	l := &Loader{r: chunkRadius, loaded: make(map[ChunkPos]*chunkData), viewer: v}
+	l := &Loader{r: chunkRadius, loaded: make(map[ChunkPos]*chunkData), sent: make(map[ChunkPos]uint64), viewer: v}
 	l.world(world)
 	return l
 }
@@ -91,7 +98,10 @@ func (l *Loader) Load(n int) {
 		pos := l.loadQueue[0]
 		c := l.w.chunk(pos)
 
-		l.viewer.ViewChunk(pos, c.Chunk, c.e)
+		if v, ok := l.sent[pos]; !ok || v != c.version {
+			l.w.viewChunk(l.viewer, pos, c)
+			l.sent[pos] = c.version
+		}
 		l.w.addViewer(c, l)
 
 		l.loaded[pos] = c
@@ -139,6 +149,7 @@ func (l *Loader) reset() {
 		l.w.removeViewer(pos, l)
 	}
 	l.loaded = map[ChunkPos]*chunkData{}
+	l.sent = map[ChunkPos]uint64{}
 	l.w.removeWorldViewer(l)
 }
 
@@ -151,32 +162,39 @@ func (l *Loader) world(new *World) {
 }
 
 // evictUnused gets rid of chunks in the loaded map which are no longer within the chunk radius of the loader,
-// and should therefore be removed.
+// and should therefore be removed. The versions of sent chunks are kept for one chunk beyond the radius, so
+// that moving back and forth over a chunk border does not result in the same chunks being sent repeatedly.
+// Beyond that, the Viewer may have discarded the chunk, so the version is forgotten.
 func (l *Loader) evictUnused() {
 	for pos := range l.loaded {
-		diffX, diffZ := pos[0]-l.pos[0], pos[1]-l.pos[1]
-		dist := math.Sqrt(float64(diffX*diffX) + float64(diffZ*diffZ))
-		if int(dist) > l.r {
+		if l.distance(pos) > l.r {
 			delete(l.loaded, pos)
 			l.w.removeViewer(pos, l)
 		}
 	}
+	for pos := range l.sent {
+		if l.distance(pos) > l.r+1 {
+			delete(l.sent, pos)
+		}
+	}
+}
+
+// distance returns the distance in chunks from the position of the loader to the ChunkPos passed.
+func (l *Loader) distance(pos ChunkPos) int {
+	diffX, diffZ := pos[0]-l.pos[0], pos[1]-l.pos[1]
+	return int(math.Sqrt(float64(diffX*diffX) + float64(diffZ*diffZ)))
 }
 
 // populateLoadQueue populates the load queue of the loader. This method is called once to create the order in
 // which chunks around the position the loader is now in should be loaded. Chunks are ordered to be loaded
 // from the middle outwards.
 func (l *Loader) populateLoadQueue() {
-	// We'll first load the chunk positions to load in a map indexed by the distance to the center (basically,
-	// what precedence it should have), and put them in the loadQueue in that order.
-	queue := map[int32][]ChunkPos{}
-
 	r := int32(l.r)
+	l.loadQueue = l.loadQueue[:0]
 	for x := -r; x <= r; x++ {
 		for z := -r; z <= r; z++ {
 			distance := math.Sqrt(float64(x*x) + float64(z*z))
-			chunkDistance := int32(math.Round(distance))
-			if chunkDistance > r {
+			if int32(math.Round(distance)) > r {
 				// The chunk was outside the chunk radius.
 				continue
 			}
@@ -185,16 +203,19 @@ func (l *Loader) populateLoadQueue() {
 				// The chunk was already loaded, so we don't need to do anything.
 				continue
 			}
-			if m, ok := queue[chunkDistance]; ok {
-				queue[chunkDistance] = append(m, pos)
-				continue
-			}
-			queue[chunkDistance] = []ChunkPos{pos}
+			l.loadQueue = append(l.loadQueue, pos)
 		}
 	}
+	// Sort the queue by the distance to the centre, so that chunks are loaded in a spiral outwards from the
+	// chunk the Loader is in, starting with that chunk and the chunks directly around it.
+	sort.SliceStable(l.loadQueue, func(i, j int) bool {
+		return l.distanceSquared(l.loadQueue[i]) < l.distanceSquared(l.loadQueue[j])
+	})
+}
 
-	l.loadQueue = l.loadQueue[:0]
-	for i := int32(0); i < r; i++ {
-		l.loadQueue = append(l.loadQueue, queue[i]...)
-	}
+// distanceSquared returns the squared distance in chunks between the ChunkPos passed and the chunk the Loader
+// is in.
+func (l *Loader) distanceSquared(pos ChunkPos) int32 {
+	x, z := pos[0]-l.pos[0], pos[1]-l.pos[1]
+	return x*x + z*z
 }
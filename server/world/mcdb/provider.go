@@ -159,19 +159,26 @@ func (p *Provider) Settings() *world.Settings {
 // returned through a call to Settings.
 func (p *Provider) loadSettings() {
 	p.set = &world.Settings{
-		Name:            p.d.LevelName,
-		Spawn:           cube.Pos{int(p.d.SpawnX), int(p.d.SpawnY), int(p.d.SpawnZ)},
-		Time:            p.d.Time,
-		TimeCycle:       p.d.DoDayLightCycle,
-		RainTime:        int64(p.d.RainTime),
-		Raining:         p.d.RainLevel > 0,
-		ThunderTime:     int64(p.d.LightningTime),
-		Thundering:      p.d.LightningLevel > 0,
-		WeatherCycle:    p.d.DoWeatherCycle,
-		CurrentTick:     p.d.CurrentTick,
-		DefaultGameMode: p.loadDefaultGameMode(),
-		Difficulty:      p.loadDifficulty(),
-		TickRange:       p.d.ServerChunkTickRange,
+		Name:              p.d.LevelName,
+		Spawn:             cube.Pos{int(p.d.SpawnX), int(p.d.SpawnY), int(p.d.SpawnZ)},
+		Seed:              p.d.RandomSeed,
+		Time:              p.d.Time,
+		TimeCycle:         p.d.DoDayLightCycle,
+		RainTime:          int64(p.d.RainTime),
+		Raining:           p.d.RainLevel > 0,
+		ThunderTime:       int64(p.d.LightningTime),
+		Thundering:        p.d.LightningLevel > 0,
+		WeatherCycle:      p.d.DoWeatherCycle,
+		CurrentTick:       p.d.CurrentTick,
+		DefaultGameMode:   p.loadDefaultGameMode(),
+		Difficulty:        p.loadDifficulty(),
+		TickRange:         p.d.ServerChunkTickRange,
+		FallDamage:        p.d.FallDamage,
+		KeepInventory:     p.d.KeepInventory,
+		ShowCoordinates:   p.d.ShowCoordinates,
+		TNTExplodes:       p.d.TNTExplodes,
+		PvP:               p.d.PVP,
+		ShowDeathMessages: p.d.ShowDeathMessages,
 	}
 }
 
@@ -180,6 +187,7 @@ func (p *Provider) SaveSettings(s *world.Settings) {
 	p.d.LevelName = s.Name
 	p.d.SpawnX, p.d.SpawnY, p.d.SpawnZ = int32(s.Spawn.X()), int32(s.Spawn.Y()), int32(s.Spawn.Z())
 	p.d.LimitedWorldOriginX, p.d.LimitedWorldOriginY, p.d.LimitedWorldOriginZ = p.d.SpawnX, p.d.SpawnY, p.d.SpawnZ
+	p.d.RandomSeed = s.Seed
 	p.d.Time = s.Time
 	p.d.DoDayLightCycle = s.TimeCycle
 	p.d.DoWeatherCycle = s.WeatherCycle
@@ -193,6 +201,12 @@ func (p *Provider) SaveSettings(s *world.Settings) {
 	}
 	p.d.CurrentTick = s.CurrentTick
 	p.d.ServerChunkTickRange = s.TickRange
+	p.d.FallDamage = s.FallDamage
+	p.d.KeepInventory = s.KeepInventory
+	p.d.ShowCoordinates = s.ShowCoordinates
+	p.d.TNTExplodes = s.TNTExplodes
+	p.d.PVP = s.PvP
+	p.d.ShowDeathMessages = s.ShowDeathMessages
 	p.saveDefaultGameMode(s.DefaultGameMode)
 	p.saveDifficulty(s.Difficulty)
 }
@@ -347,6 +361,34 @@ func (p *Provider) SaveChunk(position world.ChunkPos, c *chunk.Chunk, dim world.
 	return nil
 }
 
+// Chunks returns the positions of all chunks stored in the leveldb database for the world.Dimension passed.
+// The chunks may be read using LoadChunk, for example to process a world without loading it.
+func (p *Provider) Chunks(dim world.Dimension) ([]world.ChunkPos, error) {
+	l := 9
+	if dim.EncodeDimension() != 0 {
+		l = 13
+	}
+	var positions []world.ChunkPos
+	iter := p.db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) != l || (key[l-1] != keyVersion && key[l-1] != keyVersionOld) {
+			continue
+		}
+		pos := world.ChunkPos{int32(binary.LittleEndian.Uint32(key)), int32(binary.LittleEndian.Uint32(key[4:]))}
+		if !bytes.Equal(p.index(pos, dim), key[:l-1]) {
+			// The key belongs to a chunk in a different dimension.
+			continue
+		}
+		positions = append(positions, pos)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("error iterating chunks: %w", err)
+	}
+	return positions, nil
+}
+
 // loadDefaultGameMode returns the default game mode stored in the level.dat.
 func (p *Provider) loadDefaultGameMode() world.GameMode {
 	switch p.d.GameType {
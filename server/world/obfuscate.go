@@ -0,0 +1,130 @@
+package world
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+)
+
+// hiddenRuntimeIDs maps the runtime IDs of all block states with the same name as one of the hidden blocks
+// passed to the runtime ID of their replacement. Nil is returned if no blocks are to be hidden.
+func hiddenRuntimeIDs(hidden map[Block]Block) map[uint32]uint32 {
+	if len(hidden) == 0 {
+		return nil
+	}
+	names := make(map[string]uint32, len(hidden))
+	for b, replacement := range hidden {
+		name, _ := b.EncodeBlock()
+		names[name] = BlockRuntimeID(replacement)
+	}
+	m := make(map[uint32]uint32)
+	for rid, b := range blocks {
+		name, _ := b.EncodeBlock()
+		if replacement, ok := names[name]; ok {
+			m[uint32(rid)] = replacement
+		}
+	}
+	return m
+}
+
+// Obfuscated checks if the World hides any blocks from its viewers, as specified by the HiddenBlocks of its
+// Config.
+func (w *World) Obfuscated() bool {
+	return w != nil && w.hidden != nil
+}
+
+// Hides checks if the Block passed is one of the HiddenBlocks of the World, which are hidden from viewers
+// unless exposed.
+func (w *World) Hides(b Block) bool {
+	if !w.Obfuscated() {
+		return false
+	}
+	_, ok := w.hidden[BlockRuntimeID(b)]
+	return ok
+}
+
+// Exposes checks if the Block passed exposes the blocks around it, so that hidden blocks next to it are not
+// hidden from viewers. Blocks through which light passes, such as air, expose the blocks around them.
+func Exposes(b Block) bool {
+	return chunk.FilteringBlocks[BlockRuntimeID(b)] < 15
+}
+
+// viewChunk shows the chunk passed to the Viewer passed. If the Viewer is an ObfuscatedViewer that has blocks
+// hidden from it, the obfuscated version of the chunk is shown instead. viewChunk must be called while the
+// chunk is locked.
+func (w *World) viewChunk(v Viewer, pos ChunkPos, c *chunkData) {
+	if ov, ok := v.(ObfuscatedViewer); ok && w.Obfuscated() && ov.Obfuscated() {
+		v.ViewChunk(pos, w.obfuscateChunk(c), c.e)
+		return
+	}
+	v.ViewChunk(pos, c.Chunk, c.e)
+}
+
+// obfuscateChunk returns a copy of the chunk passed in which all HiddenBlocks of the World that are not
+// exposed to a transparent block are replaced with their replacement. Blocks at the edges of the chunk are
+// only checked for exposure against blocks in the same chunk. If the chunk does not hold any blocks that
+// should be hidden, the chunk.Chunk itself is returned. The result is cached until the chunk is modified, so
+// that it is computed only once for all viewers. obfuscateChunk must be called while the chunk is locked.
+func (w *World) obfuscateChunk(c *chunkData) *chunk.Chunk {
+	if c.obfuscated != nil && c.obfuscatedVersion == c.version {
+		return c.obfuscated
+	}
+	var cp *chunk.Chunk
+	r := c.Range()
+	for ind, sub := range c.Sub() {
+		if sub.Empty() || !w.paletteHides(sub.Layer(0).Palette()) {
+			continue
+		}
+		baseY := c.SubY(int16(ind))
+		for x := uint8(0); x < 16; x++ {
+			for z := uint8(0); z < 16; z++ {
+				for y := uint8(0); y < 16; y++ {
+					replacement, ok := w.hidden[sub.Block(x, y, z, 0)]
+					if !ok {
+						continue
+					}
+					pos := cube.Pos{int(x), int(baseY) + int(y), int(z)}
+					if w.exposed(c.Chunk, pos, r) {
+						continue
+					}
+					if cp == nil {
+						// Only copy the chunk once it turns out a block needs to be hidden.
+						var err error
+						if cp, err = chunk.DiskDecode(chunk.Encode(c.Chunk, chunk.DiskEncoding), r); err != nil {
+							w.conf.Log.Errorf("obfuscate chunk: %v", err)
+							return c.Chunk
+						}
+					}
+					cp.SetBlock(x, int16(pos[1]), z, 0, replacement)
+				}
+			}
+		}
+	}
+	if cp == nil {
+		cp = c.Chunk
+	}
+	c.obfuscated, c.obfuscatedVersion = cp, c.version
+	return cp
+}
+
+// paletteHides checks if the chunk.Palette passed holds any of the hidden blocks of the World.
+func (w *World) paletteHides(p *chunk.Palette) bool {
+	for i := 0; i < p.Len(); i++ {
+		if _, ok := w.hidden[p.Value(uint16(i))]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// exposed checks if the block at the position passed, relative to the chunk, is next to a block that exposes
+// it. Positions outside the chunk are not considered.
+func (w *World) exposed(c *chunk.Chunk, pos cube.Pos, r cube.Range) bool {
+	exposed := false
+	pos.Neighbours(func(n cube.Pos) {
+		if exposed || n[0] < 0 || n[0] > 15 || n[2] < 0 || n[2] > 15 {
+			return
+		}
+		exposed = chunk.FilteringBlocks[c.Block(uint8(n[0]), int16(n[1]), uint8(n[2]), 0)] < 15
+	}, r)
+	return exposed
+}
@@ -0,0 +1,11 @@
+package particle
+
+// Custom is a particle that is spawned by its identifier, such as 'minecraft:heart_particle' or
+// 'minecraft:villager_happy'. It may be used to show any vanilla particle effect or a particle added by a
+// resource pack that has no dedicated type in this package.
+type Custom struct {
+	particle
+
+	// Name is the identifier of the particle effect to spawn.
+	Name string
+}
@@ -0,0 +1,165 @@
+package world
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/go-gl/mathgl/mgl64"
+	"golang.org/x/exp/slices"
+)
+
+// Region is a cuboid area in a World, spanning all blocks between two corners. Regions may be added to a World
+// using World.AddRegion, after which players entering and leaving them have their handlers called.
+type Region struct {
+	// A and B are two opposite corners of the Region. Both corners are part of the Region.
+	A, B cube.Pos
+	// Protected specifies if players are prevented from breaking and placing blocks inside the Region. Players
+	// that are operators are not affected.
+	Protected bool
+}
+
+// Within checks if the block position passed is within the Region.
+func (r Region) Within(pos cube.Pos) bool {
+	for i := 0; i < 3; i++ {
+		low, high := r.A[i], r.B[i]
+		if low > high {
+			low, high = high, low
+		}
+		if pos[i] < low || pos[i] > high {
+			return false
+		}
+	}
+	return true
+}
+
+// Vec3Within checks if the position passed is within the Region.
+func (r Region) Vec3Within(pos mgl64.Vec3) bool {
+	return r.Within(cube.PosFromVec3(pos))
+}
+
+// AddRegion adds a Region with a name to the World. If a Region with the same name already exists, it is
+// replaced.
+func (w *World) AddRegion(name string, r Region) {
+	if w == nil {
+		return
+	}
+	w.regionMu.Lock()
+	defer w.regionMu.Unlock()
+	w.regions[name] = r
+}
+
+// RemoveRegion removes the Region with the name passed from the World.
+func (w *World) RemoveRegion(name string) {
+	if w == nil {
+		return
+	}
+	w.regionMu.Lock()
+	defer w.regionMu.Unlock()
+	delete(w.regions, name)
+}
+
+// Region returns the Region with the name passed. If no Region with the name was added to the World, false is
+// returned.
+func (w *World) Region(name string) (Region, bool) {
+	if w == nil {
+		return Region{}, false
+	}
+	w.regionMu.RLock()
+	defer w.regionMu.RUnlock()
+	r, ok := w.regions[name]
+	return r, ok
+}
+
+// RegionsAt returns the sorted names of all Regions in the World that the block position passed is within.
+func (w *World) RegionsAt(pos cube.Pos) []string {
+	if w == nil {
+		return nil
+	}
+	w.regionMu.RLock()
+	defer w.regionMu.RUnlock()
+	var names []string
+	for name, r := range w.regions {
+		if r.Within(pos) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// SetSpawnProtection sets the radius in blocks around the spawn of the World in which players are prevented
+// from breaking and placing blocks. Players that are operators are not affected. A radius of 0 disables spawn
+// protection, which is the default.
+func (w *World) SetSpawnProtection(radius int) {
+	if w == nil {
+		return
+	}
+	w.regionMu.Lock()
+	defer w.regionMu.Unlock()
+	w.spawnProtection = radius
+}
+
+// SpawnProtection returns the radius in blocks around the spawn of the World in which blocks are protected, as
+// set using SetSpawnProtection.
+func (w *World) SpawnProtection() int {
+	if w == nil {
+		return 0
+	}
+	w.regionMu.RLock()
+	defer w.regionMu.RUnlock()
+	return w.spawnProtection
+}
+
+// SetBedrockProtection sets if the lowest layer of blocks of the World, which generally consists of bedrock, is
+// protected, so that players cannot break and place blocks in it. Players that are operators are not affected.
+// Bedrock protection is disabled by default.
+func (w *World) SetBedrockProtection(v bool) {
+	if w == nil {
+		return
+	}
+	w.regionMu.Lock()
+	defer w.regionMu.Unlock()
+	w.bedrockProtection = v
+}
+
+// BedrockProtection checks if the lowest layer of blocks of the World is protected, as set using
+// SetBedrockProtection.
+func (w *World) BedrockProtection() bool {
+	if w == nil {
+		return false
+	}
+	w.regionMu.RLock()
+	defer w.regionMu.RUnlock()
+	return w.bedrockProtection
+}
+
+// Protected checks if the block position passed is within any protected Region of the World, within the
+// spawn protection radius of the World or in the lowest layer of the World if bedrock protection is enabled.
+func (w *World) Protected(pos cube.Pos) bool {
+	if w == nil {
+		return false
+	}
+	if pos[1] == w.Range()[0] && w.BedrockProtection() {
+		return true
+	}
+	if r := w.SpawnProtection(); r > 0 {
+		spawn := w.Spawn()
+		if abs(pos[0]-spawn[0]) <= r && abs(pos[2]-spawn[2]) <= r {
+			return true
+		}
+	}
+	w.regionMu.RLock()
+	defer w.regionMu.RUnlock()
+	for _, r := range w.regions {
+		if r.Protected && r.Within(pos) {
+			return true
+		}
+	}
+	return false
+}
+
+// abs returns the absolute value of x.
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
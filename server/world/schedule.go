@@ -0,0 +1,81 @@
+package world
+
+import (
+	"github.com/df-mc/atomic"
+)
+
+// scheduledTask is a function scheduled to be called on the goroutine that ticks a World.
+type scheduledTask struct {
+	// at is the tick at which the task is next run. interval is the amount of ticks between runs, or 0 if
+	// the task only runs once.
+	at, interval int64
+	f            func()
+	cancelled    atomic.Bool
+}
+
+// Schedule schedules f to be called once after delay ticks have passed. f is called on the goroutine that
+// ticks the World, so f should not block. Unlike the time of the World, scheduled tasks also run when there
+// are no viewers in the World. The function returned may be called to cancel the task before it runs.
+func (w *World) Schedule(delay int64, f func()) (cancel func()) {
+	return w.schedule(delay, 0, f)
+}
+
+// ScheduleRepeating schedules f to be called every interval ticks, starting after interval ticks have passed.
+// f is called on the goroutine that ticks the World, so f should not block. The function returned must be
+// called to stop calling f.
+func (w *World) ScheduleRepeating(interval int64, f func()) (cancel func()) {
+	if interval < 1 {
+		interval = 1
+	}
+	return w.schedule(interval, interval, f)
+}
+
+// schedule adds a scheduledTask to the World that is first run after delay ticks and afterwards every interval
+// ticks if interval is not 0.
+func (w *World) schedule(delay, interval int64, f func()) func() {
+	if w == nil {
+		return func() {}
+	}
+	if delay < 1 {
+		delay = 1
+	}
+	task := &scheduledTask{at: w.taskTick.Load() + delay, interval: interval, f: f}
+
+	w.taskMu.Lock()
+	w.tasks = append(w.tasks, task)
+	w.taskMu.Unlock()
+	return func() {
+		task.cancelled.Store(true)
+	}
+}
+
+// runTasks advances the task tick of the World and runs all scheduledTasks that are due. Tasks are run after
+// the lock on the tasks is released, so that tasks may schedule new tasks.
+func (t ticker) runTasks() {
+	tick := t.w.taskTick.Add(1)
+
+	t.w.taskMu.Lock()
+	var due []*scheduledTask
+	remaining := t.w.tasks[:0]
+	for _, task := range t.w.tasks {
+		if task.cancelled.Load() {
+			continue
+		}
+		if task.at <= tick {
+			due = append(due, task)
+			if task.interval == 0 {
+				continue
+			}
+			task.at = tick + task.interval
+		}
+		remaining = append(remaining, task)
+	}
+	t.w.tasks = remaining
+	t.w.taskMu.Unlock()
+
+	for _, task := range due {
+		if !task.cancelled.Load() {
+			task.f()
+		}
+	}
+}
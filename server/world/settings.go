@@ -3,6 +3,7 @@ package world
 import (
 	"github.com/df-mc/atomic"
 	"github.com/df-mc/dragonfly/server/block/cube"
+	"math/rand"
 	"sync"
 )
 
@@ -16,6 +17,9 @@ type Settings struct {
 	Name string
 	// Spawn is the spawn position of the World. New players that join the world will be spawned here.
 	Spawn cube.Pos
+	// Seed is the seed of the World. It is sent to clients, which use it for features such as biome colours,
+	// and may be used by Generators and plugins that should produce the same results every time.
+	Seed int64
 	// Time is the current time of the World. It advances every tick if TimeCycle is set to true.
 	Time int64
 	// TimeCycle specifies if the time should advance every tick. If set to false, time won't change.
@@ -41,16 +45,33 @@ type Settings struct {
 	// TickRange is the radius in chunks around a Viewer that has its blocks and entities ticked when the world is
 	// ticked. If set to 0, blocks and entities will never be ticked.
 	TickRange int32
+	// FallDamage specifies if entities in the World take damage when falling from a height.
+	FallDamage bool
+	// KeepInventory specifies if players keep their inventory and experience when they die in the World.
+	KeepInventory bool
+	// ShowCoordinates specifies if the coordinates of players in the World should be shown on their screen.
+	ShowCoordinates bool
+	// TNTExplodes specifies if TNT can be ignited and explode in the World.
+	TNTExplodes bool
+	// PvP specifies if players in the World can damage other players.
+	PvP bool
+	// ShowDeathMessages specifies if a message is broadcast to the players in the World when a player dies.
+	ShowDeathMessages bool
 }
 
 // defaultSettings returns the default Settings for a new World.
 func defaultSettings() *Settings {
 	return &Settings{
-		Name:            "World",
-		DefaultGameMode: GameModeSurvival,
-		Difficulty:      DifficultyNormal,
-		TimeCycle:       true,
-		WeatherCycle:    true,
-		TickRange:       6,
+		Name:              "World",
+		Seed:              rand.Int63(),
+		DefaultGameMode:   GameModeSurvival,
+		Difficulty:        DifficultyNormal,
+		TimeCycle:         true,
+		WeatherCycle:      true,
+		TickRange:         6,
+		FallDamage:        true,
+		TNTExplodes:       true,
+		PvP:               true,
+		ShowDeathMessages: true,
 	}
 }
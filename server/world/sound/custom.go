@@ -0,0 +1,17 @@
+package sound
+
+// Custom is a sound that is played by its name as found in the sound definitions of the client, such as
+// 'random.orb' or 'mob.villager.yes'. It may be used to play any vanilla sound or a sound added by a resource
+// pack that has no dedicated type in this package.
+type Custom struct {
+	// Name is the name of the sound to play.
+	Name string
+	// Volume is the volume of the sound. A volume of 1 is the default volume. If left as 0, the volume will
+	// default to 1.
+	Volume float64
+	// Pitch is the pitch of the sound. A pitch of 1 is the default pitch. If left as 0, the pitch will default
+	// to 1.
+	Pitch float64
+
+	sound
+}
@@ -13,17 +13,30 @@ import (
 // methods on World.
 type ticker struct{ w *World }
 
+// tpsTicks is the amount of ticks over which the ticks per second of a World are averaged.
+const tpsTicks = 100
+
 // tickLoop starts ticking the World 20 times every second, updating all entities, blocks and other features such as
 // the time and weather of the world, as required.
 func (t ticker) tickLoop() {
 	tc := time.NewTicker(time.Second / 20)
 	defer tc.Stop()
 
+	// times holds the times at which the last ticks started. The ticker drops ticks if a tick takes too long, so
+	// fewer ticks are started per second if the World can't keep up.
+	times := make([]time.Time, 0, tpsTicks)
+
 	t.w.running.Add(1)
 	for {
 		select {
 		case <-tc.C:
+			if len(times) == tpsTicks {
+				times = append(times[:0], times[1:]...)
+			}
+			times = append(times, time.Now())
+			t.measureTPS(times)
 			t.tick()
+			t.runTasks()
 		case <-t.w.closing:
 			// World is being closed: Stop ticking and get rid of a task.
 			t.w.running.Done()
@@ -32,8 +45,29 @@ func (t ticker) tickLoop() {
 	}
 }
 
+// TPS returns the average amount of ticks per second of the World over the last 5 seconds. This is 20 if the World
+// is not lagging. Ticks that are skipped because there are no viewers in the World are still counted.
+func (t ticker) TPS() float64 {
+	return t.w.tps.Load()
+}
+
+// measureTPS measures the ticks per second of the World from the start times of the last ticks passed.
+func (t ticker) measureTPS(times []time.Time) {
+	if len(times) < 2 {
+		return
+	}
+	tps := float64(len(times)-1) / times[len(times)-1].Sub(times[0]).Seconds()
+	if tps > 20 {
+		tps = 20
+	}
+	t.w.tps.Store(tps)
+}
+
 // tick performs a tick on the World and updates the time, weather, blocks and entities that require updates.
 func (t ticker) tick() {
+	if t.w.Frozen() {
+		return
+	}
 	viewers, loaders := t.w.allViewers()
 
 	t.w.set.Lock()
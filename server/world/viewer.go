@@ -66,6 +66,20 @@ type Viewer interface {
 	ViewWorldSpawn(pos cube.Pos)
 	// ViewWeather views the weather of the world, including rain and thunder.
 	ViewWeather(raining, thunder bool)
+	// ViewGamerules views the gamerules passed, indexed by their names. It is called when a gamerule of the
+	// world is changed.
+	ViewGamerules(rules map[string]any)
+	// ViewDifficulty views the difficulty of the world. It is called when the difficulty of the world is
+	// changed.
+	ViewDifficulty(d Difficulty)
+}
+
+// ObfuscatedViewer is a Viewer from which the HiddenBlocks of a World may be hidden. Chunks passed to the
+// ViewChunk method of an ObfuscatedViewer have their hidden blocks replaced if Obfuscated returns true.
+type ObfuscatedViewer interface {
+	Viewer
+	// Obfuscated checks if the HiddenBlocks of the World should currently be hidden from the Viewer.
+	Obfuscated() bool
 }
 
 // NopViewer is a Viewer implementation that does not implement any behaviour. It may be embedded by other structs to
@@ -95,5 +109,7 @@ func (NopViewer) ViewEmote(Entity, uuid.UUID)
 func (NopViewer) ViewSkin(Entity)                                               {}
 func (NopViewer) ViewWorldSpawn(cube.Pos)                                       {}
 func (NopViewer) ViewWeather(bool, bool)                                        {}
+func (NopViewer) ViewGamerules(map[string]any)                                  {}
+func (NopViewer) ViewDifficulty(Difficulty)                                     {}
 func (NopViewer) ViewFurnaceUpdate(time.Duration, time.Duration, time.Duration, time.Duration, time.Duration, time.Duration) {
 }
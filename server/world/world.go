@@ -33,6 +33,15 @@ type World struct {
 
 	set     *Settings
 	handler atomic.Value[Handler]
+	// tps holds the average amount of ticks per second of the World, as measured by the tick loop.
+	tps atomic.Float64
+
+	// taskTick is the amount of ticks that have passed since the World was created. Unlike the CurrentTick in
+	// the Settings, it also advances when there are no viewers. tasks holds the scheduledTasks added using
+	// Schedule and ScheduleRepeating.
+	taskTick atomic.Int64
+	taskMu   sync.Mutex
+	tasks    []*scheduledTask
 
 	weather
 	ticker
@@ -64,6 +73,26 @@ type World struct {
 
 	viewersMu sync.Mutex
 	viewers   map[*Loader]Viewer
+
+	regionMu sync.RWMutex
+	// regions holds the Regions added to the World, indexed by their names.
+	regions map[string]Region
+	// spawnProtection is the radius in blocks around the spawn of the World in which blocks are protected.
+	spawnProtection int
+	// bedrockProtection specifies if the lowest layer of blocks in the World is protected.
+	bedrockProtection bool
+
+	// hidden maps the runtime IDs of the blocks hidden from viewers to the runtime IDs of the blocks they are
+	// replaced with. hidden is nil if no blocks are hidden.
+	hidden map[uint32]uint32
+
+	// voidLevel is the Y level below which players are in the void. If voidTeleport is true, these players
+	// are teleported to the spawn rather than hurt.
+	voidLevel    atomic.Int64
+	voidTeleport atomic.Bool
+
+	// frozen specifies if the World is currently frozen using World.Freeze.
+	frozen atomic.Bool
 }
 
 // New creates a new initialised world. The world may be used right away, but it will not be saved or loaded
@@ -100,6 +129,51 @@ func (w *World) Range() cube.Range {
 	return w.ra
 }
 
+// SetVoidLevel sets the Y level below which players in the World are considered to be in the void. Players
+// below this level are hurt every half second, or, if teleport is true, teleported back to the spawn of the
+// World. By default, the void level is the lowest Y value of the Range of the World.
+func (w *World) SetVoidLevel(y int, teleport bool) {
+	if w == nil {
+		return
+	}
+	w.voidLevel.Store(int64(y))
+	w.voidTeleport.Store(teleport)
+}
+
+// VoidLevel returns the Y level below which players in the World are considered to be in the void, and if
+// these players are teleported back to the spawn rather than hurt, as set using SetVoidLevel.
+func (w *World) VoidLevel() (y int, teleport bool) {
+	if w == nil {
+		return 0, false
+	}
+	return int(w.voidLevel.Load()), w.voidTeleport.Load()
+}
+
+// Freeze freezes the World. While frozen, the World is no longer ticked: The time and weather do not
+// advance, entities and blocks are not ticked and block updates scheduled using ScheduleBlockUpdate are
+// rejected. Methods of the World called directly, such as SetBlock and AddEntity, are not affected, so
+// actions of players, which are handled on the goroutines of their sessions, still modify the World. The
+// World may be unfrozen again by calling World.Unfreeze.
+func (w *World) Freeze() {
+	if w == nil || w.frozen.Swap(true) {
+		return
+	}
+	w.conf.Log.Debugf("World '%v' frozen.", w.Name())
+}
+
+// Unfreeze unfreezes a World previously frozen using World.Freeze, so that it resumes ticking.
+func (w *World) Unfreeze() {
+	if w == nil || !w.frozen.Swap(false) {
+		return
+	}
+	w.conf.Log.Debugf("World '%v' unfrozen.", w.Name())
+}
+
+// Frozen checks if the World is currently frozen using World.Freeze.
+func (w *World) Frozen() bool {
+	return w != nil && w.frozen.Load()
+}
+
 // EntityRegistry returns the EntityRegistry that was passed to the World's
 // Config upon construction.
 func (w *World) EntityRegistry() EntityRegistry {
@@ -245,7 +319,7 @@ func (w *World) SetBlock(pos cube.Pos, b Block, opts *SetOpts) {
 		before = c.Block(x, y, z, 0)
 	}
 
-	c.m = true
+	c.markModified()
 	c.SetBlock(x, y, z, 0, rid)
 	if nbtBlocks[rid] {
 		c.e[pos] = b
@@ -302,7 +376,7 @@ func (w *World) SetBiome(pos cube.Pos, b Biome) {
 	c := w.chunk(chunkPosFromBlockPos(pos))
 	defer c.Unlock()
 
-	c.m = true
+	c.markModified()
 	c.SetBiome(uint8(pos[0]), int16(pos[1]), uint8(pos[2]), uint32(b.EncodeBiome()))
 }
 
@@ -386,18 +460,57 @@ func (w *World) BuildStructure(pos cube.Pos, s Structure) {
 				}
 			}
 			c.SetBlock(0, 0, 0, 0, c.Block(0, 0, 0, 0)) // Make sure the heightmap is recalculated.
-			c.m = true
+			c.markModified()
 
 			// After setting all blocks of the structure within a single chunk, we show the new chunk to all
 			// viewers once, and unlock it.
 			for _, viewer := range c.v {
-				viewer.ViewChunk(chunkPos, c.Chunk, c.e)
+				w.viewChunk(viewer, chunkPos, c)
 			}
 			c.Unlock()
 		}
 	}
 }
 
+// SetBlocks sets all blocks in the map passed at their respective positions. Edits are grouped per chunk, and each
+// chunk is locked while all of its edits are applied, so that no partially edited chunk can be observed. Instead of
+// sending an update for every block, every affected chunk is resent to its viewers once.
+// Like BuildStructure, SetBlocks does not displace liquids or update blocks around the positions edited. Positions
+// out of the bounds of the World are ignored.
+func (w *World) SetBlocks(edits map[cube.Pos]Block) {
+	if w == nil {
+		return
+	}
+	chunks := make(map[ChunkPos][]cube.Pos)
+	for pos := range edits {
+		if pos.OutOfBounds(w.Range()) {
+			continue
+		}
+		chunkPos := chunkPosFromBlockPos(pos)
+		chunks[chunkPos] = append(chunks[chunkPos], pos)
+	}
+	for chunkPos, positions := range chunks {
+		c := w.chunk(chunkPos)
+		for _, pos := range positions {
+			b := edits[pos]
+			rid := BlockRuntimeID(b)
+			c.SetBlock(uint8(pos[0]), int16(pos[1]), uint8(pos[2]), 0, rid)
+			if nbtBlocks[rid] {
+				c.e[pos] = b
+			} else {
+				delete(c.e, pos)
+			}
+		}
+		c.markModified()
+
+		// After setting all blocks within a single chunk, we show the new chunk to all viewers once, and unlock it.
+		for _, viewer := range c.v {
+			w.viewChunk(viewer, chunkPos, c)
+		}
+		c.Unlock()
+	}
+}
+
 // Liquid attempts to return any liquid block at the position passed. This liquid may be in the foreground or
 // in any other layer.
 // If found, the liquid is returned. If not, the bool returned is false and the liquid is nil.
@@ -443,6 +556,7 @@ func (w *World) SetLiquid(pos cube.Pos, b Liquid) {
 	c := w.chunk(chunkPos)
 	if b == nil {
 		w.removeLiquids(c, pos)
+		c.markModified()
 		c.Unlock()
 		w.doBlockUpdatesAround(pos)
 		return
@@ -466,7 +580,7 @@ func (w *World) SetLiquid(pos cube.Pos, b Liquid) {
 			v.ViewBlockUpdate(pos, b, 1)
 		}
 	}
-	c.m = true
+	c.markModified()
 	c.Unlock()
 
 	w.doBlockUpdatesAround(pos)
@@ -565,6 +679,19 @@ func (w *World) SkyLight(pos cube.Pos) uint8 {
 	return c.SkyLight(uint8(pos[0]), int16(pos[1]), uint8(pos[2]))
 }
 
+// BlockLight returns the block light level at the position passed. Unlike SkyLight, this light level is only
+// influenced by blocks that emit light, such as torches or glowstone. The light value is a value in the range
+// 0-15, where 0 means no light is present.
+func (w *World) BlockLight(pos cube.Pos) uint8 {
+	if w == nil || pos[1] < w.Range()[0] || pos[1] > w.Range()[1] {
+		// Outside the world, so there are no blocks that could emit light.
+		return 0
+	}
+	c := w.chunk(chunkPosFromBlockPos(pos))
+	defer c.Unlock()
+	return c.BlockLight(uint8(pos[0]), int16(pos[1]), uint8(pos[2]))
+}
+
 // Time returns the current time of the world. The time is incremented every 1/20th of a second, unless
 // World.StopTime() is called.
 func (w *World) Time() int {
@@ -670,12 +797,16 @@ func (w *World) AddEntity(e Entity) {
 		return
 	}
 
+	chunkPos := chunkPosFromVec3(e.Position())
+	if !w.entityAllowed(e, chunkPos) {
+		return
+	}
+
 	// Remove the Entity from any previous World it might be in.
 	e.World().RemoveEntity(e)
 
 	add(e, w)
 
-	chunkPos := chunkPosFromVec3(e.Position())
 	w.entityMu.Lock()
 	w.entities[e] = chunkPos
 	w.entityMu.Unlock()
@@ -693,6 +824,50 @@ func (w *World) AddEntity(e Entity) {
 	w.Handler().HandleEntitySpawn(e)
 }
 
+// entityAllowed checks if the Entity passed may be added to the chunk at the ChunkPos passed, considering the
+// MaxChunkEntities of the World. Players and items are always allowed, so that no items are lost, as items
+// already merge with each other in crowded chunks. The first time an Entity is refused in a chunk, a warning
+// is logged with the location of the chunk. It is logged again once the chunk dropped below the limit and
+// filled up again.
+func (w *World) entityAllowed(e Entity, pos ChunkPos) bool {
+	if name := e.Type().EncodeEntity(); w.conf.MaxChunkEntities <= 0 || name == "minecraft:player" || name == "minecraft:item" {
+		return true
+	}
+	c := w.chunk(pos)
+	full, logged := len(c.entities) >= w.conf.MaxChunkEntities, c.entityLimitLogged
+	c.entityLimitLogged = full
+	c.Unlock()
+
+	if full && !logged {
+		w.conf.Log.Warnf("world %v: chunk %v (x=%v, z=%v) reached the limit of %v entities, refusing to spawn %v", w.Name(), pos, pos[0]<<4, pos[1]<<4, w.conf.MaxChunkEntities, e.Type().EncodeEntity())
+	}
+	return !full
+}
+
+// EntityCount returns the number of entities in the chunk at the ChunkPos passed. If the chunk is not
+// loaded, EntityCount returns 0.
+func (w *World) EntityCount(pos ChunkPos) int {
+	if w == nil {
+		return 0
+	}
+	c, ok := w.chunkFromCache(pos)
+	if !ok {
+		return 0
+	}
+	defer c.Unlock()
+	return len(c.entities)
+}
+
+// Crowded checks if the chunk at the position passed holds more entities than the EntityMergeThreshold of
+// the World. Entities that support it, such as dropped items, merge with identical entities anywhere in
+// crowded chunks.
+func (w *World) Crowded(pos mgl64.Vec3) bool {
+	if w == nil || w.conf.EntityMergeThreshold <= 0 {
+		return false
+	}
+	return w.EntityCount(chunkPosFromVec3(pos)) > w.conf.EntityMergeThreshold
+}
+
 // add maps an Entity to a World in the entityWorlds map.
 func add(e Entity, w *World) {
 	worldsMu.Lock()
@@ -724,6 +899,10 @@ func (w *World) RemoveEntity(e Entity) {
 	delete(entityWorlds, e)
 	worldsMu.Unlock()
 
+	w.entityMu.Lock()
+	delete(w.entities, e)
+	w.entityMu.Unlock()
+
 	c, ok := w.chunkFromCache(chunkPos)
 	if !ok {
 		// The chunk wasn't loaded, so we can't remove any entity from the chunk.
@@ -733,10 +912,6 @@ func (w *World) RemoveEntity(e Entity) {
 	viewers := slices.Clone(c.v)
 	c.Unlock()
 
-	w.entityMu.Lock()
-	delete(w.entities, e)
-	w.entityMu.Unlock()
-
 	for _, v := range viewers {
 		v.HideEntity(e)
 	}
@@ -910,20 +1085,37 @@ func (w *World) Difficulty() Difficulty {
 	return w.set.Difficulty
 }
 
-// SetDifficulty changes the difficulty of a world.
+// Seed returns the seed of the world. Generators and plugins may use it to produce the same terrain or
+// structures every time the world is loaded.
+func (w *World) Seed() int64 {
+	if w == nil {
+		return 0
+	}
+	w.set.Lock()
+	defer w.set.Unlock()
+	return w.set.Seed
+}
+
+// SetDifficulty changes the difficulty of a world. The new difficulty is sent to all viewers of the world.
 func (w *World) SetDifficulty(d Difficulty) {
 	if w == nil {
 		return
 	}
 	w.set.Lock()
-	defer w.set.Unlock()
 	w.set.Difficulty = d
+	w.set.Unlock()
+
+	viewers, _ := w.allViewers()
+	for _, viewer := range viewers {
+		viewer.ViewDifficulty(d)
+	}
 }
 
 // ScheduleBlockUpdate schedules a block update at the position passed after a specific delay. If the block at
 // that position does not handle block updates, nothing will happen.
+// Block updates scheduled while the World is frozen are discarded.
 func (w *World) ScheduleBlockUpdate(pos cube.Pos, delay time.Duration) {
-	if w == nil || pos.OutOfBounds(w.Range()) {
+	if w == nil || pos.OutOfBounds(w.Range()) || w.Frozen() {
 		return
 	}
 	w.updateMu.Lock()
@@ -1050,6 +1242,118 @@ func (w *World) close() {
 	}
 }
 
+// LoadedChunks returns the amount of chunks currently loaded in the World.
+func (w *World) LoadedChunks() int {
+	if w == nil {
+		return 0
+	}
+	w.chunkMu.Lock()
+	defer w.chunkMu.Unlock()
+	return len(w.chunks)
+}
+
+// ChunkLoaded checks if the chunk at the ChunkPos passed is currently loaded. Unlike most other methods of the
+// World, ChunkLoaded never loads or generates the chunk.
+func (w *World) ChunkLoaded(pos ChunkPos) bool {
+	if w == nil {
+		return false
+	}
+	w.chunkMu.Lock()
+	defer w.chunkMu.Unlock()
+	_, ok := w.chunks[pos]
+	return ok
+}
+
+// ForEachChunk calls the function passed for every chunk currently loaded in the World, until f returns false.
+// The chunk is locked while f is called, so f must not call methods of the World that access blocks in the
+// same chunk. Changes made to the chunk.Chunk directly are not sent to viewers or saved, so ForEachChunk should
+// be used only to read chunks, for example to export or analyse them. Chunks that are not loaded may be read
+// from the Provider of the World instead, such as with mcdb.Provider.Chunks.
+func (w *World) ForEachChunk(f func(pos ChunkPos, c *chunk.Chunk) bool) {
+	if w == nil {
+		return
+	}
+	w.chunkMu.Lock()
+	chunks := maps.Clone(w.chunks)
+	w.chunkMu.Unlock()
+
+	for pos, c := range chunks {
+		c.Lock()
+		cont := f(pos, c.Chunk)
+		c.Unlock()
+		if !cont {
+			return
+		}
+	}
+}
+
+// PreGenerate loads, or generates if they do not yet exist, all chunks within a square radius around the
+// center ChunkPos passed, so that players joining the World afterwards do not have to wait for them to be
+// generated. PreGenerate blocks until all chunks are loaded and logs its progress. The chunks are kept loaded
+// until they have had no viewers for the ChunkUnloadDelay of the Config, so PreGenerate is typically called
+// right before the Server starts accepting players.
+func (w *World) PreGenerate(center ChunkPos, radius int) {
+	if w == nil || radius < 0 {
+		return
+	}
+	total, n := (radius*2+1)*(radius*2+1), 0
+	w.conf.Log.Debugf("Pre-generating %v chunks around %v...", total, center)
+	for x := center[0] - int32(radius); x <= center[0]+int32(radius); x++ {
+		for z := center[1] - int32(radius); z <= center[1]+int32(radius); z++ {
+			w.chunk(ChunkPos{x, z}).Unlock()
+			if n++; n%(total/10+1) == 0 {
+				w.conf.Log.Debugf("Pre-generating chunks: %v%% done.", n*100/total)
+			}
+		}
+	}
+	w.conf.Log.Debugf("Pre-generated %v chunks around %v.", total, center)
+}
+
+// Save saves all chunks currently loaded in the World that were modified, along with the entities in them and
+// the settings of the World, to the Provider. Unlike Close, Save keeps the chunks loaded. Save does nothing if
+// the World is read-only.
+func (w *World) Save() {
+	if w == nil || w.conf.ReadOnly {
+		return
+	}
+	w.chunkMu.Lock()
+	toSave := maps.Clone(w.chunks)
+	w.chunkMu.Unlock()
+
+	n := 0
+	for pos, c := range toSave {
+		c.Lock()
+		if w.writeChunk(pos, c) {
+			n++
+		}
+		c.Unlock()
+	}
+	w.conf.Log.Debugf("Saved %v modified chunks to disk.", n)
+
+	if w.advance {
+		w.set.Lock()
+		w.provider().SaveSettings(w.set)
+		w.set.Unlock()
+	}
+}
+
+// autoSave runs until the world is closed, saving the World every time the SaveInterval of its Config passes.
+func (w *World) autoSave() {
+	t := time.NewTicker(w.conf.SaveInterval)
+	defer t.Stop()
+
+	w.running.Add(1)
+	for {
+		select {
+		case <-t.C:
+			w.Save()
+		case <-w.closing:
+			w.running.Done()
+			return
+		}
+	}
+}
+
 // allViewers returns a list of all loaders of the world, regardless of where in the world they are viewing.
 func (w *World) allViewers() ([]Viewer, []*Loader) {
 	w.viewersMu.Lock()
@@ -1074,6 +1378,7 @@ func (w *World) addWorldViewer(l *Loader) {
 	w.set.Unlock()
 	l.viewer.ViewWeather(raining, thundering)
 	l.viewer.ViewWorldSpawn(w.Spawn())
+	l.viewer.ViewDifficulty(w.Difficulty())
 }
 
 // removeWorldViewer removes a viewer from the world. Should only be used while the viewer isn't viewing any chunks.
@@ -1336,34 +1641,7 @@ func (w *World) loadIntoBlocks(c *chunkData, blockEntityData []map[string]any) {
 func (w *World) saveChunk(pos ChunkPos, c *chunkData) {
 	c.Lock()
 	if !w.conf.ReadOnly {
-		if len(c.e) > 0 || c.m {
-			c.Compact()
-			if err := w.provider().SaveChunk(pos, c.Chunk, w.conf.Dim); err != nil {
-				w.conf.Log.Errorf("error saving chunk %v to provider: %v", pos, err)
-			}
-
-			m := make([]map[string]any, 0, len(c.e))
-			for pos, b := range c.e {
-				if n, ok := b.(NBTer); ok {
-					data := n.EncodeNBT()
-					data["x"], data["y"], data["z"] = int32(pos[0]), int32(pos[1]), int32(pos[2])
-					m = append(m, data)
-				}
-			}
-			if err := w.provider().SaveBlockNBT(pos, m, w.conf.Dim); err != nil {
-				w.conf.Log.Errorf("error saving block NBT in chunk %v to provider: %v", pos, err)
-			}
-		}
-
-		s := make([]Entity, 0, len(c.entities))
-		for _, e := range c.entities {
-			if _, ok := e.Type().(SaveableEntityType); ok {
-				s = append(s, e)
-			}
-		}
-		if err := w.provider().SaveEntities(pos, s, w.conf.Dim); err != nil {
-			w.conf.Log.Errorf("error saving entities in chunk %v to provider: %v", pos, err)
-		}
+		w.writeChunk(pos, c)
 	}
 	ent := c.entities
 	c.entities = nil
@@ -1374,22 +1652,63 @@ func (w *World) saveChunk(pos ChunkPos, c *chunkData) {
 	}
 }
 
+// writeChunk writes the chunk, block entities and entities of the chunkData passed to the provider. The blocks
+// of the chunk are only written if it was modified or has block entities, in which case writeChunk returns
+// true. writeChunk must only be called while the chunkData is locked.
+func (w *World) writeChunk(pos ChunkPos, c *chunkData) bool {
+	written := false
+	if len(c.e) > 0 || c.m {
+		c.Compact()
+		if err := w.provider().SaveChunk(pos, c.Chunk, w.conf.Dim); err != nil {
+			w.conf.Log.Errorf("error saving chunk %v to provider: %v", pos, err)
+		}
+
+		if err := w.provider().SaveBlockNBT(pos, encodeBlockNBT(c.e), w.conf.Dim); err != nil {
+			w.conf.Log.Errorf("error saving block NBT in chunk %v to provider: %v", pos, err)
+		}
+		c.m, written = false, true
+	}
+
+	s := make([]Entity, 0, len(c.entities))
+	for _, e := range c.entities {
+		if _, ok := e.Type().(SaveableEntityType); ok {
+			s = append(s, e)
+		}
+	}
+	if err := w.provider().SaveEntities(pos, s, w.conf.Dim); err != nil {
+		w.conf.Log.Errorf("error saving entities in chunk %v to provider: %v", pos, err)
+	}
+	return written
+}
+
 // chunkCacheJanitor runs until the world is running, cleaning chunks that are no longer in use from the cache.
+// Chunks are removed once they have had no viewers for at least the ChunkUnloadDelay of the Config.
 func (w *World) chunkCacheJanitor() {
-	t := time.NewTicker(time.Minute * 5)
+	interval := w.conf.ChunkUnloadDelay / 4
+	if interval < time.Second {
+		interval = time.Second
+	}
+	t := time.NewTicker(interval)
 	defer t.Stop()
 
 	w.running.Add(1)
 	chunksToRemove := map[ChunkPos]*chunkData{}
 	for {
 		select {
-		case <-t.C:
+		case now := <-t.C:
 			w.chunkMu.Lock()
 			for pos, c := range w.chunks {
 				c.Lock()
-				v := len(c.v)
+				unused := len(c.v) == 0
+				if !unused {
+					c.unused = time.Time{}
+				} else if c.unused.IsZero() {
+					c.unused, unused = now, false
+				} else {
+					unused = now.Sub(c.unused) >= w.conf.ChunkUnloadDelay
+				}
 				c.Unlock()
-				if v == 0 {
+				if unused {
 					chunksToRemove[pos] = c
 					delete(w.chunks, pos)
 					if w.lastPos == pos {
@@ -1410,6 +1729,9 @@ func (w *World) chunkCacheJanitor() {
 	}
 }
 
+// chunkVersion is a counter used to assign a unique version to chunks every time they are loaded or modified.
+var chunkVersion atomic.Uint64
+
 // chunkData represents the data of a chunk including the block entities and loaders. This data is protected
 // by the mutex present in the chunk.Chunk held.
 type chunkData struct {
@@ -1419,6 +1741,20 @@ type chunkData struct {
 	v        []Viewer
 	l        []*Loader
 	entities []Entity
+	// entityLimitLogged specifies if an Entity was refused because the chunk reached the MaxChunkEntities of
+	// the World, so that this is only logged once until the chunk drops below the limit again.
+	entityLimitLogged bool
+
+	// version is the version of the chunk. It changes every time the chunk is modified, so that a Loader can
+	// find out if the chunk changed since it was last sent to its Viewer.
+	version uint64
+	// obfuscated is the chunk with the HiddenBlocks of the World replaced, as computed for obfuscatedVersion.
+	// It is shared by all viewers that have blocks hidden from them.
+	obfuscated        *chunk.Chunk
+	obfuscatedVersion uint64
+	// unused is the time at which the chunk was first found to have no viewers by the chunk cache janitor. It
+	// is reset once the chunk has viewers again.
+	unused time.Time
 }
 
 // BlockEntities returns the block entities of the chunk.
@@ -1442,7 +1778,14 @@ func (c *chunkData) Entities() []Entity {
 	return slices.Clone(c.entities)
 }
 
+// markModified marks the chunkData as modified, so that it is saved when unloaded, and assigns it a new
+// version.
+func (c *chunkData) markModified() {
+	c.m = true
+	c.version = chunkVersion.Inc()
+}
+
 // newChunkData returns a new chunkData wrapper around the chunk.Chunk passed.
 func newChunkData(c *chunk.Chunk) *chunkData {
-	return &chunkData{Chunk: c, e: map[cube.Pos]Block{}}
+	return &chunkData{Chunk: c, e: map[cube.Pos]Block{}, version: chunkVersion.Inc()}
 }